@@ -0,0 +1,371 @@
+package goradix
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidFormat is returned when serialized data can not be decoded.
+var ErrInvalidFormat = errors.New("goradix: invalid binary format")
+
+// binaryMagic starts serialized trees.
+const binaryMagic = "GRDX"
+
+// binaryVersion is the version of the binary format of serialized trees.
+const binaryVersion = 1
+
+// node flags of the binary format
+const (
+	flagHasValue byte = 1 << iota
+	flagHasDisplayKey
+
+	knownFlags = flagHasValue | flagHasDisplayKey
+)
+
+// ValueCodec encodes and decodes values of a tree for serialization.
+type ValueCodec[V any] interface {
+	EncodeValue(value V) ([]byte, error)
+	DecodeValue(data []byte) (V, error)
+}
+
+// GobCodec is a ValueCodec based on encoding/gob. Concrete types stored
+// in interface values should be registered with gob.Register.
+type GobCodec[V any] struct{}
+
+// EncodeValue encodes the given value with encoding/gob.
+func (GobCodec[V]) EncodeValue(value V) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeValue decodes a value encoded by EncodeValue.
+func (GobCodec[V]) DecodeValue(data []byte) (value V, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+
+	return value, err
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. Edges, values and
+// suggestions sets of all nodes are serialized, so the tree is restored
+// without calling any SuggestionFunc. Values are encoded by the codec set
+// with WithValueCodec.
+func (rt *Tree[V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if _, err := rt.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It replaces the content of the tree with the decoded one.
+func (rt *Tree[V]) UnmarshalBinary(data []byte) error {
+	_, err := rt.ReadFrom(bytes.NewReader(data))
+
+	return err
+}
+
+// WriteTo writes the tree in the binary format to w.
+// It implements io.WriterTo.
+func (rt *Tree[V]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	codec := rt.valueCodec()
+
+	// nodes are numbered in preorder, suggestions refer to these numbers
+	nodes := []*Tree[V]{}
+	index := map[*Tree[V]]uint64{}
+
+	var number func(rt *Tree[V])
+
+	number = func(rt *Tree[V]) {
+		index[rt] = uint64(len(nodes))
+		nodes = append(nodes, rt)
+
+		for i := range rt.edges {
+			number(rt.edges[i].radixTree)
+		}
+	}
+
+	number(rt)
+
+	bw.WriteString(binaryMagic)
+	bw.WriteByte(binaryVersion)
+	writeUvarint(bw, uint64(len(nodes)))
+
+	for _, node := range nodes {
+		flags := byte(0)
+
+		if node.hasValue {
+			flags |= flagHasValue
+		}
+
+		display, hasDisplay := rt.displayKey(node)
+		if hasDisplay {
+			flags |= flagHasDisplayKey
+		}
+
+		bw.WriteByte(flags)
+
+		if node.hasValue {
+			value, err := codec.EncodeValue(node.value)
+			if err != nil {
+				return cw.n, fmt.Errorf("goradix: encode value: %w", err)
+			}
+
+			writeBytes(bw, value)
+		}
+
+		if hasDisplay {
+			writeBytes(bw, []byte(display))
+		}
+
+		writeUvarint(bw, uint64(len(node.edges)))
+
+		for i := range node.edges {
+			writeBytes(bw, []byte(node.edges[i].label))
+		}
+	}
+
+	for _, node := range nodes {
+		writeUvarint(bw, uint64(len(node.suggestions)))
+
+		for _, s := range node.suggestions {
+			i, ok := index[s]
+			if !ok {
+				return cw.n, errors.New(
+					"goradix: suggestion refers to a node out of the tree")
+			}
+
+			writeUvarint(bw, i)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+
+	return cw.n, nil
+}
+
+// ReadFrom reads the tree in the binary format from r and replaces
+// the content of the tree with it. It implements io.ReaderFrom.
+// r may be read beyond the end of the tree. A tree in grapheme clusters
+// mode must be read into a tree created with WithGraphemeClusters.
+func (rt *Tree[V]) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	br := bufio.NewReader(cr)
+	codec := rt.valueCodec()
+
+	magic := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return cr.n, invalidFormat(err)
+	}
+
+	if string(magic[:len(binaryMagic)]) != binaryMagic ||
+		magic[len(binaryMagic)] != binaryVersion {
+		return cr.n, ErrInvalidFormat
+	}
+
+	count, err := binary.ReadUvarint(br)
+	if err != nil || count == 0 {
+		return cr.n, invalidFormat(err)
+	}
+
+	root := New[V]()
+	nodes := make([]*Tree[V], 0, min(count, 1<<20))
+	displays := map[*Tree[V]]string{}
+
+	var readNode func(rt *Tree[V]) error
+
+	readNode = func(rt *Tree[V]) error {
+		if uint64(len(nodes)) == count {
+			return ErrInvalidFormat
+		}
+
+		nodes = append(nodes, rt)
+
+		flags, err := br.ReadByte()
+		if err != nil || flags&^knownFlags != 0 {
+			return invalidFormat(err)
+		}
+
+		if flags&flagHasValue != 0 {
+			data, err := readBytes(br)
+			if err != nil {
+				return err
+			}
+
+			value, err := codec.DecodeValue(data)
+			if err != nil {
+				return fmt.Errorf("goradix: decode value: %w", err)
+			}
+
+			rt.setValue(value)
+		}
+
+		if flags&flagHasDisplayKey != 0 {
+			display, err := readBytes(br)
+			if err != nil {
+				return err
+			}
+
+			displays[rt] = string(display)
+		}
+
+		edges, err := binary.ReadUvarint(br)
+		if err != nil || edges > count {
+			return invalidFormat(err)
+		}
+
+		labels := make([][]byte, edges)
+
+		for i := range labels {
+			if labels[i], err = readBytes(br); err != nil {
+				return err
+			}
+		}
+
+		for i := range labels {
+			child := New[V]()
+			e := newEdge[V]().
+				SetLabel(string(labels[i])).
+				SetRadixTree(child).
+				SetParent(rt)
+
+			child.setParent(e)
+			rt.edges = append(rt.edges, e)
+
+			if err := readNode(child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := readNode(root); err != nil {
+		return cr.n, err
+	}
+
+	if uint64(len(nodes)) != count {
+		return cr.n, ErrInvalidFormat
+	}
+
+	for _, node := range nodes {
+		suggestions, err := binary.ReadUvarint(br)
+		if err != nil || suggestions > count {
+			return cr.n, invalidFormat(err)
+		}
+
+		node.suggestions = make([]*Tree[V], suggestions)
+
+		for i := range node.suggestions {
+			j, err := binary.ReadUvarint(br)
+			if err != nil || j >= count {
+				return cr.n, invalidFormat(err)
+			}
+
+			node.suggestions[i] = nodes[j]
+		}
+	}
+
+	// the root of the decoded tree becomes rt
+	for _, e := range root.edges {
+		e.SetParent(rt)
+	}
+
+	for _, node := range nodes {
+		node.replaceSuggestion(root, rt)
+	}
+
+	rt.value, rt.hasValue = root.value, root.hasValue
+	rt.edges, rt.suggestions = root.edges, root.suggestions
+
+	if display, ok := displays[root]; ok {
+		delete(displays, root)
+		displays[rt] = display
+	}
+
+	switch {
+	case len(displays) != 0 && rt.options == nil:
+		rt.options = &options[V]{displayKeys: displays}
+	case rt.options != nil:
+		rt.options.displayKeys = displays
+	}
+
+	return cr.n, nil
+}
+
+// invalidFormat wraps the given error of reading serialized data.
+func invalidFormat(err error) error {
+	if err == nil {
+		return ErrInvalidFormat
+	}
+
+	return fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+}
+
+func writeUvarint(w *bufio.Writer, x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+
+	w.Write(buf[:binary.PutUvarint(buf[:], x)])
+}
+
+func writeBytes(w *bufio.Writer, b []byte) {
+	writeUvarint(w, uint64(len(b)))
+	w.Write(b)
+}
+
+func readBytes(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, invalidFormat(err)
+	}
+
+	// data is read by chunks, so a broken size does not allocate much
+	data, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil || uint64(len(data)) != size {
+		return nil, invalidFormat(err)
+	}
+
+	return data, nil
+}
+
+// countingWriter counts bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err
+}
+
+// countingReader counts bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,121 @@
+package goradix
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// intCodec encodes int values as decimal strings.
+type intCodec struct{}
+
+func (intCodec) EncodeValue(value int) ([]byte, error) {
+	return []byte(strconv.Itoa(value)), nil
+}
+
+func (intCodec) DecodeValue(data []byte) (int, error) {
+	return strconv.Atoi(string(data))
+}
+
+func binaryTree(opts ...Option[int]) *Tree[int] {
+	rt := New[int](opts...)
+
+	asf := func(
+		key string, currentSuggestions []*Tree[int], condidate *Tree[int],
+	) []*Tree[int] {
+		if condidate.Value() >= 10 {
+			return append(currentSuggestions, condidate)
+		}
+
+		return currentSuggestions
+	}
+
+	for key, value := range map[string]int{
+		"": 0, "rube": 100, "ruber": 200, "rubens": 3, "rubi": 4,
+		"rubicundus": 500, "rubicon": 60, "слово": 70,
+	} {
+		rt.InsertWithAddSuggestionFunction(key, value, asf)
+	}
+
+	return rt
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for name, opts := range map[string][]Option[int]{
+		"gob":    nil,
+		"custom": {WithValueCodec[int](intCodec{})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rt := binaryTree(opts...)
+
+			data, err := rt.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+
+			got := New[int](opts...)
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+
+			if got.StringValues() != rt.StringValues() {
+				t.Errorf("UnmarshalBinary() = \n%s\nwant\n%s",
+					got.StringValues(), rt.StringValues())
+			}
+
+			for _, prefix := range []string{"", "r", "rub", "rubi", "rubicu", "с"} {
+				want := rt.ClosestSuggestions(prefix)
+				if s := got.ClosestSuggestions(prefix); !reflect.DeepEqual(s, want) {
+					t.Errorf("ClosestSuggestions(%q) = %v; want %v", prefix, s, want)
+				}
+			}
+
+			checkInvariants(t, got, false)
+		})
+	}
+}
+
+func TestBinaryUntyped(t *testing.T) {
+	rt := NewRadixTree()
+	rt.InsertWithAddSuggestionFunction("one", 1, acceptAll[interface{}])
+	rt.InsertWithAddSuggestionFunction("two", "2", acceptAll[interface{}])
+	rt.InsertWithAddSuggestionFunction("nil", nil, acceptAll[interface{}])
+
+	var buf bytes.Buffer
+
+	if _, err := rt.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	got := NewRadixTree()
+	if _, err := got.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+
+	want := []Suggestion{{"nil", nil}, {"one", 1}, {"two", "2"}}
+	if s := got.AutoCompleteDepthTraversal("", 0); !reflect.DeepEqual(s, want) {
+		t.Errorf("AutoCompleteDepthTraversal() = %v; want %v", s, want)
+	}
+
+	checkInvariants(t, got, true)
+}
+
+func TestBinaryInvalidFormat(t *testing.T) {
+	data, err := binaryTree().MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	for i := 0; i < len(data); i++ {
+		if err := New[int]().UnmarshalBinary(data[:i]); !errors.Is(err, ErrInvalidFormat) {
+			t.Fatalf("UnmarshalBinary(data[:%d]) error = %v; want %v",
+				i, err, ErrInvalidFormat)
+		}
+	}
+
+	if err := New[int]().UnmarshalBinary([]byte("GRDX\x02")); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("UnmarshalBinary() of unknown version error = %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package goradix
+
+import (
+	"errors"
+	"iter"
+)
+
+// ErrUnsortedKeys is returned by BuildFromSorted when keys are not
+// strictly increasing.
+var ErrUnsortedKeys = errors.New("goradix: keys are not sorted")
+
+// BuildFromSorted builds a tree from key-value pairs sorted by keys in
+// strictly increasing order. With WithKeyNormalizer keys must be sorted
+// by the normalized form, with WithGraphemeClusters keys are compared by
+// grapheme clusters. The tree is built bottom-up in a single pass,
+// so it is much faster than inserting the same pairs one by one.
+//
+// Suggestions set of each node is built from suggestions sets of its
+// children: p is called for each member of children's sets instead of each
+// node with value of the subtree. The tree is identical to the one built by
+// InsertWithAddSuggestionFunction in the same order if p only filters or
+// selects candidates (like accepting all nodes, nodes with big enough
+// values or top K ones), which is the usual case. The top-K policy set by
+// WithTopK takes place of p.
+func BuildFromSorted[V any](
+	seq iter.Seq2[string, V], p SuggestionFunc[V], opts ...Option[V],
+) (*Tree[V], error) {
+	// pending is a node on the path to the last key,
+	// it still can get new children
+	type pending struct {
+		node *Tree[V]
+		key  string
+	}
+
+	root := New[V](opts...)
+	if t := root.topKPolicy(); t != nil {
+		p = t.suggestionFunc()
+	}
+
+	stack := []pending{{root, ""}}
+	prev, first := "", true
+
+	// pop finishes the node on the top of the stack
+	pop := func() pending {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		top.node.buildSuggestions(top.key, top.node == root, p)
+
+		return top
+	}
+
+	for display, value := range seq {
+		key := root.normalize(display)
+
+		if !first && root.compareKeys(key, prev) <= 0 {
+			return nil, ErrUnsortedKeys
+		}
+
+		first = false
+
+		if key == "" {
+			root.setValue(value)
+			root.setDisplayKey(root, display, key)
+
+			continue
+		}
+
+		lcp := len(root.units().commonPrefix(prev, key))
+		prev = key
+
+		// nodes deeper than the common prefix do not get new children
+		var last *pending
+
+		for len(stack[len(stack)-1].key) > lcp {
+			top := pop()
+			last = &top
+		}
+
+		// key: hello last: head
+		if parent := stack[len(stack)-1]; len(parent.key) < lcp {
+			e := last.node.parent
+
+			split := New[V]().setParent(e)
+			nedge := newEdge[V]().
+				SetLabel(last.key[lcp:]).
+				SetRadixTree(last.node).
+				SetParent(split)
+
+			last.node.setParent(nedge)
+			split.edges = []*edge[V]{nedge}
+
+			e.SetLabel(key[len(parent.key):lcp]).SetRadixTree(split)
+
+			stack = append(stack, pending{split, key[:lcp]})
+		}
+
+		parent := stack[len(stack)-1]
+
+		// edges come in increasing order, so they stay sorted
+		node := New[V]().setValue(value)
+		root.setDisplayKey(node, display, key)
+		e := newEdge[V]().
+			SetLabel(key[len(parent.key):]).
+			SetRadixTree(node).
+			SetParent(parent.node)
+
+		node.setParent(e)
+		parent.node.edges = append(parent.node.edges, e)
+
+		stack = append(stack, pending{node, key})
+	}
+
+	for len(stack) != 0 {
+		pop()
+	}
+
+	return root, nil
+}
+
+// buildSuggestions builds suggestions set of the node from suggestions
+// sets of its children the same way as insert does for sorted keys.
+// A node with value gets itself and then nodes of children's sets.
+// A node without value is created by a split, so it takes the set of
+// the first child and then nodes of other children's sets.
+func (rt *Tree[V]) buildSuggestions(key string, root bool, p SuggestionFunc[V]) {
+	edges := rt.edges
+
+	switch {
+	case rt.hasValue:
+		rt.addSuggestion(key, rt, p)
+	case !root && len(edges) != 0:
+		rt.setSuggestions(edges[0].radixTree.suggestions)
+		edges = edges[1:]
+	}
+
+	for _, e := range edges {
+		rt.addSuggestions(key, e.radixTree.suggestions, p)
+	}
+}
+
+// compareKeys compares keys in the order of edges: bytewise or
+// by grapheme clusters in grapheme clusters mode.
+func (rt *Tree[V]) compareKeys(a string, b string) int {
+	return rt.units().compare(a, b)
+}
@@ -0,0 +1,162 @@
+package goradix
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// firstTwo is a suggestion function which keeps two first candidates.
+func firstTwo(
+	key string, currentSuggestions []*Tree[int], condidate *Tree[int],
+) []*Tree[int] {
+	if len(currentSuggestions) < 2 {
+		return append(currentSuggestions, condidate)
+	}
+
+	return currentSuggestions
+}
+
+// evenOnly is a suggestion function which accepts even values only.
+func evenOnly(
+	key string, currentSuggestions []*Tree[int], condidate *Tree[int],
+) []*Tree[int] {
+	if condidate.Value()%2 == 0 {
+		return append(currentSuggestions, condidate)
+	}
+
+	return currentSuggestions
+}
+
+// suggestionSets returns suggestions sets of all nodes of the tree
+// by keys of the nodes.
+func suggestionSets(rt *Tree[int]) map[string][]KeyValue[int] {
+	sets := map[string][]KeyValue[int]{}
+
+	var walk func(node *Tree[int], key string)
+
+	walk = func(node *Tree[int], key string) {
+		sets[key] = rt.ClosestSuggestions(key)
+
+		for _, e := range node.edges {
+			walk(e.radixTree, key+e.label)
+		}
+	}
+
+	walk(rt, "")
+
+	return sets
+}
+
+// checkSameAsInserted compares the tree built from sorted pairs with
+// the tree built by insertion of the same pairs.
+func checkSameAsInserted(t *testing.T, pairs map[string]int) {
+	t.Helper()
+
+	keys := slices.Sorted(maps.Keys(pairs))
+
+	for name, p := range map[string]SuggestionFunc[int]{
+		"nil": nil, "all": acceptAll[int], "first two": firstTwo, "even": evenOnly,
+	} {
+		want := New[int]()
+		for _, key := range keys {
+			want.InsertWithAddSuggestionFunction(key, pairs[key], p)
+		}
+
+		got, err := BuildFromSorted(func(yield func(string, int) bool) {
+			for _, key := range keys {
+				if !yield(key, pairs[key]) {
+					return
+				}
+			}
+		}, p)
+		if err != nil {
+			t.Fatalf("BuildFromSorted(%v) error = %v", keys, err)
+		}
+
+		checkInvariants(t, got, name == "all")
+
+		if got.StringValues() != want.StringValues() {
+			t.Fatalf("BuildFromSorted(%v) with %s = \n%s\nwant\n%s",
+				keys, name, got.StringValues(), want.StringValues())
+		}
+
+		if g, w := suggestionSets(got), suggestionSets(want); !reflect.DeepEqual(g, w) {
+			t.Fatalf("BuildFromSorted(%v) with %s suggestions = %v; want %v",
+				keys, name, g, w)
+		}
+	}
+}
+
+func TestBuildFromSorted(t *testing.T) {
+	checkSameAsInserted(t, map[string]int{})
+	checkSameAsInserted(t, map[string]int{"": 1})
+	checkSameAsInserted(t, map[string]int{
+		"": 0, "rube": 100, "ruber": 200, "rubens": 3, "rubi": 4,
+		"rubicundus": 500, "rubicon": 60, "romane": 7, "romanus": 8,
+		"romulus": 9, "слово": 70, "слон": 11,
+	})
+
+	for _, keys := range [][]string{{"b", "a"}, {"a", "a"}} {
+		_, err := BuildFromSorted(func(yield func(string, int) bool) {
+			for _, key := range keys {
+				if !yield(key, 0) {
+					return
+				}
+			}
+		}, nil)
+		if err != ErrUnsortedKeys {
+			t.Errorf("BuildFromSorted(%v) error = %v; want %v",
+				keys, err, ErrUnsortedKeys)
+		}
+	}
+}
+
+func FuzzBuildFromSorted(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		pairs := map[string]int{}
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			pairs[key] = value
+		})
+
+		checkSameAsInserted(t, pairs)
+	})
+}
+
+func BenchmarkBuildFromSorted(b *testing.B) {
+	keys := make([]string, 0, 1<<16)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, "key"+strconv.Itoa(i*7919))
+	}
+
+	slices.Sort(keys)
+
+	seq := func(yield func(string, int) bool) {
+		for i, key := range keys {
+			if !yield(key, i) {
+				return
+			}
+		}
+	}
+
+	b.Run("insert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rt := New[int]()
+			for key, value := range seq {
+				rt.InsertWithAddSuggestionFunction(key, value, firstTwo)
+			}
+		}
+	})
+
+	b.Run("build", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BuildFromSorted(seq, firstTwo)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package goradix
+
+// RadixTree is a radix tree holding values of any type.
+// It is kept for compatibility with the untyped API, use Tree instead.
+type RadixTree = Tree[interface{}]
+
+// Suggestion represents key-value pair of the untyped RadixTree.
+type Suggestion = KeyValue[interface{}]
+
+// AddSuggestionFunction is a signature of the functions which
+// determines new suggestion set of the untyped RadixTree.
+type AddSuggestionFunction = SuggestionFunc[interface{}]
+
+// NewRadixTree creates a new empty radix tree.
+func NewRadixTree() *RadixTree {
+	return New[interface{}]()
+}
+
+// SyncRadixTree is a concurrency-safe radix tree holding values of any type.
+type SyncRadixTree = SyncTree[interface{}]
+
+// NewSyncRadixTree creates a new empty concurrency-safe radix tree.
+func NewSyncRadixTree() *SyncRadixTree {
+	return NewSyncTree[interface{}]()
+}
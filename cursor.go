@@ -0,0 +1,291 @@
+package goradix
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+)
+
+// Cursor is an opaque continuation of paginated queries. The empty cursor
+// starts a query, the empty cursor returned with a page means there are
+// no more results.
+type Cursor string
+
+// ErrInvalidCursor is returned by paginated queries when the cursor is
+// malformed or belongs to another query.
+var ErrInvalidCursor = errors.New("goradix: invalid cursor")
+
+// cursorClosest marks cursors of ClosestSuggestionsPage, cursors of
+// autocomplete traversals are marked by their traversal mode.
+const cursorClosest = 2
+
+// cursor is a decoded Cursor: the query it belongs to and the last
+// returned key with its level in broad mode or its position in
+// the suggestions set.
+type cursor struct {
+	kind     byte
+	query    string
+	position int
+	key      string
+}
+
+// encode returns the cursor as an opaque string.
+func (c cursor) encode() Cursor {
+	data := []byte{c.kind}
+	data = binary.AppendUvarint(data, uint64(len(c.query)))
+	data = append(data, c.query...)
+	data = binary.AppendUvarint(data, uint64(c.position))
+	data = append(data, c.key...)
+
+	return Cursor(base64.RawURLEncoding.EncodeToString(data))
+}
+
+// decodeCursor decodes the cursor of the query of the given kind.
+// The empty cursor is decoded to the start of the query.
+func decodeCursor(c Cursor, kind byte, query string) (cursor, bool, error) {
+	if c == "" {
+		return cursor{}, false, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil || len(data) == 0 || data[0] != kind {
+		return cursor{}, false, ErrInvalidCursor
+	}
+
+	data = data[1:]
+
+	n, size := binary.Uvarint(data)
+	if size <= 0 || n > uint64(len(data)-size) ||
+		string(data[size:size+int(n)]) != query {
+		return cursor{}, false, ErrInvalidCursor
+	}
+
+	data = data[size+int(n):]
+
+	position, size := binary.Uvarint(data)
+	if size <= 0 || position > math.MaxInt32 {
+		return cursor{}, false, ErrInvalidCursor
+	}
+
+	return cursor{
+		kind:     kind,
+		query:    query,
+		position: int(position),
+		key:      string(data[size:]),
+	}, true, nil
+}
+
+// AutoCompleteBroadTraversalPage returns the page of at most max results
+// of AutoCompleteBroadTraversal continuing the given cursor and the cursor
+// of the next page. Non-positive max means no limit.
+// The cursor keeps the level of the last returned key, inserting or
+// deleting keys above returned ones shifts their levels, so the next page
+// of a changed tree can repeat or skip keys.
+func (rt *Tree[V]) AutoCompleteBroadTraversalPage(
+	str string, max int, c Cursor,
+) ([]KeyValue[V], Cursor, error) {
+	return rt.autoCompletePage(str, max, c, traversalModeBroad)
+}
+
+// AutoCompleteDepthTraversalPage returns the page of at most max results
+// of AutoCompleteDepthTraversal continuing the given cursor and the cursor
+// of the next page. Non-positive max means no limit.
+func (rt *Tree[V]) AutoCompleteDepthTraversalPage(
+	str string, max int, c Cursor,
+) ([]KeyValue[V], Cursor, error) {
+	return rt.autoCompletePage(str, max, c, traversalModeDepth)
+}
+
+// autoCompletePage resumes the traversal after the last returned key
+// instead of keeping the traversal queue. Depth traversal is the preorder
+// of the subtree, so it continues from the next key in order and the
+// cursor stays valid after the tree is changed. Broad traversal visits
+// levels of nodes with values one by one and each level in order of keys,
+// so it continues from the next key of the stored level, which is valid
+// only while levels of returned keys are not changed.
+func (rt *Tree[V]) autoCompletePage(
+	str string, max int, c Cursor, traversalMode traversalMode,
+) ([]KeyValue[V], Cursor, error) {
+	// found is a node with value, its key and its level
+	type found struct {
+		*Tree[V]
+		key   string
+		level int
+	}
+
+	str = rt.normalize(str)
+
+	from, ok, err := decodeCursor(c, byte(traversalMode), str)
+	if err != nil {
+		return nil, "", err
+	}
+
+	node, key := rt.lookupPrefix(str)
+	if node == nil {
+		return []KeyValue[V]{}, "", nil
+	}
+
+	if ok && !strings.HasPrefix(from.key, key) {
+		return nil, "", ErrInvalidCursor
+	}
+
+	// one more node tells whether there is the next page
+	page := []found{}
+
+	full := func() bool {
+		return max > 0 && len(page) > max
+	}
+
+	u := rt.units()
+
+	switch traversalMode {
+	case traversalModeDepth:
+		rest := ""
+		if ok {
+			rest = from.key[len(key):]
+		}
+
+		node.seek(key, rest, u, func(node *Tree[V], k string) bool {
+			if !ok || k != from.key {
+				page = append(page, found{node, k, 0})
+			}
+
+			return !full()
+		})
+	case traversalModeBroad:
+		level, after := from.position, from.key
+
+		for {
+			exists := node.walkLevel(key, level, after, u, func(node *Tree[V], k string) bool {
+				page = append(page, found{node, k, level})
+
+				return !full()
+			})
+
+			if full() || !exists && after == "" {
+				break
+			}
+
+			level, after = level+1, ""
+		}
+	}
+
+	next := Cursor("")
+
+	if full() {
+		page = page[:max]
+		last := page[max-1]
+		next = cursor{byte(traversalMode), str, last.level, last.key}.encode()
+	}
+
+	out := make([]KeyValue[V], len(page))
+	for i, f := range page {
+		out[i] = KeyValue[V]{Key: rt.display(f.Tree, f.key), Value: f.value}
+	}
+
+	return out, next, nil
+}
+
+// walkLevel calls fn for each node with value of the given level which key
+// is greater than after in order of keys until fn returns false. The level
+// of a node is the number of its upper nodes with values in the subtree.
+// It reports whether nodes of the level are met. Keys are compared by
+// the given units of the tree.
+func (rt *Tree[V]) walkLevel(
+	key string, level int, after string, u units,
+	fn func(node *Tree[V], key string) bool,
+) bool {
+	exists, stopped := false, false
+
+	var visit func(node *Tree[V], key string, depth int)
+
+	visit = func(node *Tree[V], key string, depth int) {
+		if node.hasValue {
+			if depth == level {
+				exists = true
+
+				if after == "" || u.compare(key, after) > 0 {
+					stopped = !fn(node, key)
+				}
+
+				return
+			}
+
+			depth++
+		}
+
+		for _, e := range node.edges {
+			k := key + e.label
+
+			// all keys of the subtree are less than after
+			if after != "" && u.compare(k, after) < 0 && !strings.HasPrefix(after, k) {
+				continue
+			}
+
+			if visit(e.radixTree, k, depth); stopped {
+				return
+			}
+		}
+	}
+
+	visit(rt, key, 0)
+
+	return exists
+}
+
+// ClosestSuggestionsPage returns the page of at most max results of
+// ClosestSuggestions continuing the given cursor and the cursor of the next
+// page. The page continues after the last returned suggestion or at its
+// position if the suggestion has left the set. Non-positive max means
+// no limit.
+func (rt *Tree[V]) ClosestSuggestionsPage(
+	str string, max int, c Cursor,
+) ([]KeyValue[V], Cursor, error) {
+	str = rt.normalize(str)
+
+	from, ok, err := decodeCursor(c, cursorClosest, str)
+	if err != nil {
+		return nil, "", err
+	}
+
+	node, _ := rt.lookupPrefix(str)
+	if node == nil {
+		return []KeyValue[V]{}, "", nil
+	}
+
+	set := node.suggestions
+	start := 0
+
+	if ok {
+		start = min(from.position, len(set))
+
+		for i, s := range set {
+			if s.key() == from.key {
+				start = i + 1
+
+				break
+			}
+		}
+	}
+
+	end := len(set)
+	if max > 0 {
+		end = min(start+max, end)
+	}
+
+	out := make([]KeyValue[V], 0, end-start)
+
+	for _, s := range set[start:end] {
+		key := s.key()
+		out = append(out, KeyValue[V]{Key: rt.display(s, key), Value: s.value})
+	}
+
+	next := Cursor("")
+	if end < len(set) {
+		next = cursor{cursorClosest, str, end, set[end-1].key()}.encode()
+	}
+
+	return out, next, nil
+}
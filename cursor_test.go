@@ -0,0 +1,155 @@
+package goradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+// pager is a paginated query.
+type pager func(str string, max int, c Cursor) ([]KeyValue[int], Cursor, error)
+
+// collectPages returns all results of the query read by pages of size max.
+func collectPages(t *testing.T, name string, query pager, str string, max int) []KeyValue[int] {
+	t.Helper()
+
+	out := []KeyValue[int]{}
+	c := Cursor("")
+
+	for i := 0; ; i++ {
+		page, next, err := query(str, max, c)
+		if err != nil {
+			t.Fatalf("%s(%q, %d) page %d error = %v", name, str, max, i, err)
+		}
+
+		if max > 0 && len(page) > max {
+			t.Fatalf("%s(%q, %d) page %d has %d results", name, str, max, i, len(page))
+		}
+
+		if next != "" && len(page) != max {
+			t.Fatalf("%s(%q, %d) page %d is not full but has the next one", name, str, max, i)
+		}
+
+		out = append(out, page...)
+
+		if next == "" {
+			return out
+		}
+
+		c = next
+	}
+}
+
+// checkPages compares paginated queries with unpaginated ones.
+func checkPages(t *testing.T, rt *Tree[int], str string, max int) {
+	t.Helper()
+
+	queries := []struct {
+		name  string
+		page  pager
+		whole func(str string) []KeyValue[int]
+	}{
+		{"AutoCompleteBroadTraversalPage", rt.AutoCompleteBroadTraversalPage,
+			func(str string) []KeyValue[int] { return rt.AutoCompleteBroadTraversal(str, 0) }},
+		{"AutoCompleteDepthTraversalPage", rt.AutoCompleteDepthTraversalPage,
+			func(str string) []KeyValue[int] { return rt.AutoCompleteDepthTraversal(str, 0) }},
+		{"ClosestSuggestionsPage", rt.ClosestSuggestionsPage, rt.ClosestSuggestions},
+	}
+
+	for _, q := range queries {
+		got := collectPages(t, q.name, q.page, str, max)
+		if want := q.whole(str); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s(%q, %d) pages = %v; want %v", q.name, str, max, got, want)
+		}
+	}
+}
+
+func TestPagination(t *testing.T) {
+	rt := New[int]()
+	for i, key := range []string{
+		"rube", "ruber", "rubens", "rubi", "rubicundus", "rubicon",
+		"romane", "romanus", "romulus", "слово", "слон",
+	} {
+		rt.InsertWithAddSuggestionFunction(key, i, acceptAll[int])
+	}
+
+	for _, str := range []string{"", "r", "rub", "rube", "rubic", "x", "сл"} {
+		for max := 0; max <= 4; max++ {
+			checkPages(t, rt, str, max)
+		}
+	}
+
+	page, c, err := rt.AutoCompleteDepthTraversalPage("rub", 2, "")
+	if err != nil || c == "" {
+		t.Fatalf("AutoCompleteDepthTraversalPage() = %v, %q, %v", page, c, err)
+	}
+
+	if want := []string{"rube", "rubens"}; !reflect.DeepEqual(keysOf(page), want) {
+		t.Errorf("AutoCompleteDepthTraversalPage() = %q; want %q", keysOf(page), want)
+	}
+
+	// the cursor continues after the last key when the tree is changed
+	rt.Delete("rubens")
+	rt.Insert("rubea", 100)
+	rt.Insert("rubeo", 101)
+
+	page, _, err = rt.AutoCompleteDepthTraversalPage("rub", 2, c)
+	if want := []string{"rubeo", "ruber"}; err != nil || !reflect.DeepEqual(keysOf(page), want) {
+		t.Errorf("AutoCompleteDepthTraversalPage() after change = %q, %v; want %q",
+			keysOf(page), err, want)
+	}
+
+	// cursors belong to their queries
+	for _, tt := range []struct {
+		name string
+		page pager
+		str  string
+		c    Cursor
+	}{
+		{"other query", rt.AutoCompleteDepthTraversalPage, "rom", c},
+		{"other mode", rt.AutoCompleteBroadTraversalPage, "rub", c},
+		{"closest", rt.ClosestSuggestionsPage, "rub", c},
+		{"garbage", rt.AutoCompleteDepthTraversalPage, "rub", "!!"},
+		{"truncated", rt.AutoCompleteDepthTraversalPage, "rub", c[:3]},
+	} {
+		if _, _, err := tt.page(tt.str, 2, tt.c); err != ErrInvalidCursor {
+			t.Errorf("%s cursor error = %v; want %v", tt.name, err, ErrInvalidCursor)
+		}
+	}
+}
+
+func TestPaginationNormalizer(t *testing.T) {
+	rt := normalizedTree()
+
+	page, c, err := rt.AutoCompleteDepthTraversalPage("RUB", 2, "")
+	if err != nil || !reflect.DeepEqual(keysOf(page), []string{"Rubén", "Rubens"}) {
+		t.Fatalf("AutoCompleteDepthTraversalPage() = %v, %v", page, err)
+	}
+
+	page, c, err = rt.AutoCompleteDepthTraversalPage("rúb", 2, c)
+	if err != nil || c != "" || !reflect.DeepEqual(keysOf(page), []string{"Ruber", "rubicon"}) {
+		t.Fatalf("AutoCompleteDepthTraversalPage() next = %v, %q, %v", page, c, err)
+	}
+
+	for max := 1; max <= 3; max++ {
+		checkPages(t, rt, "rub", max)
+	}
+}
+
+func FuzzPagination(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2}, "", 1)
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0}, "a", 2)
+
+	f.Fuzz(func(t *testing.T, data []byte, str string, max int) {
+		rt := New[int]()
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			if op == 3 {
+				rt.Delete(key)
+			} else {
+				rt.InsertWithAddSuggestionFunction(key, value, firstTwo)
+			}
+		})
+
+		checkPages(t, rt, str, max%5)
+	})
+}
@@ -120,16 +120,14 @@ func example2() {
 }
 
 func example3() {
-	rt := goradix.NewRadixTree()
+	rt := goradix.New[int]()
 
 	afs := func(
 		key string,
-		currentSuggestions []*goradix.RadixTree,
-		condidate *goradix.RadixTree,
-	) []*goradix.RadixTree {
-		v := condidate.Value().(int)
-
-		if v >= 10 {
+		currentSuggestions []*goradix.Tree[int],
+		condidate *goradix.Tree[int],
+	) []*goradix.Tree[int] {
+		if condidate.Value() >= 10 {
 			return append(currentSuggestions, condidate)
 		}
 
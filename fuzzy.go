@@ -0,0 +1,168 @@
+package goradix
+
+import "unicode/utf8"
+
+// FuzzyAutoComplete returns values of keys which start with str written
+// with at most maxEdits typos. A typo is an insertion, a deletion or
+// a substitution of a rune or a transposition of two adjacent runes
+// (optimal string alignment distance). Suggestions are ordered by
+// the distance and then by keys. Non-positive max means no limit.
+//
+// Edges are walked with a row of the distance matrix per rune, subtrees
+// which prefixes are too far from str are skipped.
+func (rt *Tree[V]) FuzzyAutoComplete(
+	str string, maxEdits int, max int,
+) []KeyValue[V] {
+	if maxEdits < 0 {
+		return []KeyValue[V]{}
+	}
+
+	// root keeps original keys, rt is shadowed by nodes below
+	root := rt
+
+	target := []rune(rt.normalize(str))
+	n := len(target)
+
+	// the empty prefix of each key is at most n typos far from str,
+	// so there are no needs in more typos
+	maxEdits = min(maxEdits, n)
+
+	// rows[i] is distances between the first i runes of the key
+	// and prefixes of str
+	rows := [][]int{make([]int, n+1)}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	runes := []rune{}
+	key := []byte{}
+
+	// buckets[d] holds suggestions with the distance d, there are no
+	// needs in suggestions with the distance more than limit
+	buckets := make([][]KeyValue[V], maxEdits+1)
+	limit := maxEdits
+
+	full := func(d int) bool {
+		return max > 0 && len(buckets[d]) >= max
+	}
+
+	add := func(d int, key string, value V) {
+		buckets[d] = append(buckets[d], KeyValue[V]{Key: key, Value: value})
+
+		if max <= 0 {
+			return
+		}
+
+		for count, i := 0, 0; i < limit; i++ {
+			if count += len(buckets[i]); count >= max {
+				limit = i
+			}
+		}
+	}
+
+	// row computes the row of the distance matrix for the next rune
+	row := func(r rune) []int {
+		i := len(runes) + 1
+		if i == len(rows) {
+			rows = append(rows, make([]int, n+1))
+		}
+
+		prev, cur := rows[i-1], rows[i]
+		cur[0] = i
+
+		for j := 1; j <= n; j++ {
+			cost := 1
+			if target[j-1] == r {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && r == target[j-2] && runes[i-2] == target[j-1] {
+				cur[j] = min(cur[j], rows[i-2][j-2]+1)
+			}
+		}
+
+		runes = append(runes, r)
+
+		return cur
+	}
+
+	// collect adds all values of the subtree with the distance d
+	collect := func(rt *Tree[V], key string, d int) {
+		rt.walk(root, key, func(key string, value V) bool {
+			if d > limit || full(d) {
+				return false
+			}
+
+			add(d, key, value)
+
+			return true
+		})
+	}
+
+	var walk func(rt *Tree[V], best int)
+
+	// best is the distance of the closest prefix of the key
+	walk = func(rt *Tree[V], best int) {
+		if rt.hasValue && best <= limit && !full(best) {
+			add(best, root.display(rt, string(key)), rt.value)
+		}
+
+		for _, e := range rt.edges {
+			depth, size := len(runes), len(key)
+			edgeBest := best
+
+			for label := e.label; ; {
+				if label == "" {
+					walk(e.radixTree, edgeBest)
+
+					break
+				}
+
+				r, width := utf8.DecodeRuneInString(label)
+				cur := row(r)
+				key = append(key, label[:width]...)
+				label = label[width:]
+
+				edgeBest = min(edgeBest, cur[n])
+
+				// the distance only grows with the key
+				if lower := minOf(cur); lower > limit || lower >= edgeBest {
+					if edgeBest <= limit {
+						collect(e.radixTree, string(key)+label, edgeBest)
+					}
+
+					break
+				}
+			}
+
+			runes, key = runes[:depth], key[:size]
+		}
+	}
+
+	walk(rt, rows[0][n])
+
+	out := []KeyValue[V]{}
+
+	for d := 0; d <= limit; d++ {
+		out = append(out, buckets[d]...)
+	}
+
+	if max > 0 && len(out) > max {
+		out = out[:max]
+	}
+
+	return out
+}
+
+// minOf returns the minimum of the row.
+func minOf(row []int) int {
+	m := row[0]
+
+	for _, x := range row[1:] {
+		m = min(m, x)
+	}
+
+	return m
+}
@@ -0,0 +1,137 @@
+package goradix
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// osaDistance is the reference optimal string alignment distance.
+func osaDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
+
+// fuzzyReference returns expected result of FuzzyAutoComplete.
+func fuzzyReference(rt *Tree[int], str string, maxEdits, max int) []KeyValue[int] {
+	type match struct {
+		KeyValue[int]
+		d int
+	}
+
+	matches := []match{}
+
+	for key, value := range rt.All() {
+		runes, d := []rune(key), maxEdits+1
+
+		for i := 0; i <= len(runes); i++ {
+			d = min(d, osaDistance(string(runes[:i]), str))
+		}
+
+		if d <= maxEdits {
+			matches = append(matches, match{KeyValue[int]{key, value}, d})
+		}
+	}
+
+	// All yields keys in order, so the sort keeps the order for equal distances
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].d < matches[j].d
+	})
+
+	out := []KeyValue[int]{}
+	for _, m := range matches {
+		if max > 0 && len(out) == max {
+			break
+		}
+
+		out = append(out, m.KeyValue)
+	}
+
+	return out
+}
+
+func TestFuzzyAutoComplete(t *testing.T) {
+	rt := binaryTree()
+
+	tests := []struct {
+		str      string
+		maxEdits int
+		max      int
+		want     []KeyValue[int]
+	}{
+		{"rubcion", 1, 0, []KeyValue[int]{{"rubicon", 60}}},
+		{"rubcion", 0, 0, []KeyValue[int]{}},
+		{"rubo", 1, 3, []KeyValue[int]{{"rube", 100}, {"rubens", 3}, {"ruber", 200}}},
+		{"cлово", 1, 0, []KeyValue[int]{{"слово", 70}}},
+		{"rubicund", -1, 0, []KeyValue[int]{}},
+	}
+
+	for _, tt := range tests {
+		got := rt.FuzzyAutoComplete(tt.str, tt.maxEdits, tt.max)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FuzzyAutoComplete(%q, %d, %d) = %v; want %v",
+				tt.str, tt.maxEdits, tt.max, got, tt.want)
+		}
+	}
+
+	// every key is at most as many typos far from str as str has runes
+	for _, str := range []string{"", "ru", "слов"} {
+		got := rt.FuzzyAutoComplete(str, 1<<62, 0)
+		want := fuzzyReference(rt, str, len([]rune(str)), 0)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FuzzyAutoComplete(%q, 1<<62, 0) = %v; want %v", str, got, want)
+		}
+	}
+}
+
+func FuzzFuzzyAutoComplete(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2}, []byte{1, 0, 2}, 1, 3)
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0}, []byte{4, 0}, 2, 0)
+
+	f.Fuzz(func(t *testing.T, data []byte, query []byte, maxEdits int, max int) {
+		maxEdits, max = maxEdits%4, max%5
+
+		rt := New[int]()
+		fuzzOps(data, func(op byte, key string, value int) {
+			rt.Insert(key, value)
+		})
+
+		str := ""
+		for _, b := range query {
+			str += fuzzAlphabet[int(b)%len(fuzzAlphabet)]
+		}
+
+		want := fuzzyReference(rt, str, maxEdits, max)
+		if got := rt.FuzzyAutoComplete(str, maxEdits, max); !reflect.DeepEqual(got, want) {
+			t.Fatalf("FuzzyAutoComplete(%q, %d, %d) = %v; want %v",
+				str, maxEdits, max, got, want)
+		}
+	})
+}
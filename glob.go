@@ -0,0 +1,280 @@
+package goradix
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrBadPattern is returned by Match when the pattern is malformed.
+var ErrBadPattern = errors.New("goradix: syntax error in pattern")
+
+// globKind is a kind of a glob pattern item.
+type globKind int
+
+const (
+	globLiteral globKind = iota
+	globAny
+	globStar
+	globClass
+)
+
+// globItem is an item of a compiled glob pattern.
+type globItem struct {
+	kind    globKind
+	r       rune
+	ranges  [][2]rune
+	negated bool
+}
+
+// matches reports whether the item which is not a star matches the rune.
+func (it globItem) matches(r rune) bool {
+	switch it.kind {
+	case globLiteral:
+		return it.r == r
+	case globClass:
+		for _, rng := range it.ranges {
+			if rng[0] <= r && r <= rng[1] {
+				return !it.negated
+			}
+		}
+
+		return it.negated
+	}
+
+	return true
+}
+
+// glob is a compiled glob pattern. States of its automaton are indexes
+// of items which are still to be matched, the state len(items) accepts.
+type glob []globItem
+
+// compileGlob parses the pattern:
+//
+//	'*'         matches any sequence of runes
+//	'?'         matches any single rune
+//	'[' [ '^' | '!' ] { lo [ '-' hi ] } ']'
+//	            matches a single rune of the class or out of it
+//	'\\' c      matches c
+//	c           matches c
+func compileGlob(pattern string) (glob, error) {
+	g := glob{}
+
+	// next returns the next rune of the pattern unescaping it
+	next := func() (rune, bool) {
+		if pattern == "" {
+			return 0, false
+		}
+
+		r, size := utf8.DecodeRuneInString(pattern)
+		if r == '\\' {
+			if pattern = pattern[size:]; pattern == "" {
+				return 0, false
+			}
+
+			r, size = utf8.DecodeRuneInString(pattern)
+		}
+
+		pattern = pattern[size:]
+
+		return r, true
+	}
+
+	for pattern != "" {
+		switch pattern[0] {
+		case '*':
+			pattern = pattern[1:]
+
+			if len(g) == 0 || g[len(g)-1].kind != globStar {
+				g = append(g, globItem{kind: globStar})
+			}
+		case '?':
+			pattern = pattern[1:]
+			g = append(g, globItem{kind: globAny})
+		case '[':
+			pattern = pattern[1:]
+			it := globItem{kind: globClass}
+
+			if pattern != "" && (pattern[0] == '^' || pattern[0] == '!') {
+				it.negated, pattern = true, pattern[1:]
+			}
+
+			for pattern == "" || pattern[0] != ']' || len(it.ranges) == 0 {
+				lo, ok := next()
+				if !ok {
+					return nil, ErrBadPattern
+				}
+
+				hi := lo
+
+				if pattern != "" && pattern[0] == '-' {
+					pattern = pattern[1:]
+
+					if hi, ok = next(); !ok || hi < lo {
+						return nil, ErrBadPattern
+					}
+				}
+
+				it.ranges = append(it.ranges, [2]rune{lo, hi})
+			}
+
+			pattern = pattern[1:]
+			g = append(g, it)
+		default:
+			r, ok := next()
+			if !ok {
+				return nil, ErrBadPattern
+			}
+
+			g = append(g, globItem{kind: globLiteral, r: r})
+		}
+	}
+
+	return g, nil
+}
+
+// closure adds to the states the states reachable by skipping stars.
+// A star state stays in the set, so it keeps matching.
+func (g glob) closure(states []int) []int {
+	for i := 0; i < len(states); i++ {
+		if s := states[i]; s < len(g) && g[s].kind == globStar && !g.has(states, s+1) {
+			states = append(states, s+1)
+		}
+	}
+
+	return states
+}
+
+// step returns the states after matching the rune and reports whether
+// the key still can match.
+func (g glob) step(states []int, r rune) ([]int, bool) {
+	next := make([]int, 0, len(states))
+
+	for _, s := range states {
+		var to int
+
+		switch {
+		case s == len(g):
+			continue
+		case g[s].kind == globStar:
+			to = s
+		case g[s].matches(r):
+			to = s + 1
+		default:
+			continue
+		}
+
+		if !g.has(next, to) {
+			next = append(next, to)
+		}
+	}
+
+	return g.closure(next), len(next) != 0
+}
+
+// has reports whether the states hold the state.
+func (g glob) has(states []int, state int) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accepts reports whether the whole key is matched.
+func (g glob) accepts(states []int) bool {
+	return g.has(states, len(g))
+}
+
+// acceptsAll reports whether any continuation of the key is matched,
+// which is true when the trailing star is reached.
+func (g glob) acceptsAll(states []int) bool {
+	return len(g) != 0 && g[len(g)-1].kind == globStar && g.has(states, len(g)-1)
+}
+
+// Match returns key-value pairs which keys match the glob pattern in
+// lexicographic order of keys. '*' matches any sequence of runes, '?'
+// matches any rune, '[...]' matches a rune of the class like "[a-c]"
+// or out of it like "[^a-c]", '\\' escapes special runes. The pattern is
+// normalized by the normalizer of the tree. Subtrees which keys can not
+// match the pattern are skipped. Non-positive max means no limit.
+func (rt *Tree[V]) Match(pattern string, max int) ([]KeyValue[V], error) {
+	g, err := compileGlob(rt.normalize(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	return matchAutomaton[V, []int](rt, g, g.closure([]int{0}), max), nil
+}
+
+// automaton matches keys stepping rune by rune along edges of a tree.
+type automaton[S any] interface {
+	// step returns the state after matching the rune and reports
+	// whether the key still can match
+	step(state S, r rune) (S, bool)
+	// accepts reports whether the whole key is matched
+	accepts(state S) bool
+	// acceptsAll reports whether any continuation of the key is matched
+	acceptsAll(state S) bool
+}
+
+// matchAutomaton returns key-value pairs which keys are matched by
+// the automaton in lexicographic order of keys. Subtrees which keys are
+// already rejected are skipped. Non-positive max means no limit.
+func matchAutomaton[V, S any](
+	rt *Tree[V], a automaton[S], start S, max int,
+) []KeyValue[V] {
+	out := make([]KeyValue[V], 0, suggestionsCap(max))
+	key := []byte{}
+	root := rt
+
+	add := func(key string, value V) bool {
+		out = append(out, KeyValue[V]{Key: key, Value: value})
+
+		return max <= 0 || len(out) < max
+	}
+
+	var walk func(rt *Tree[V], state S) bool
+
+	// walk returns false if the result is full
+	walk = func(rt *Tree[V], state S) bool {
+		if a.acceptsAll(state) {
+			return rt.walk(root, string(key), add)
+		}
+
+		if rt.hasValue && a.accepts(state) &&
+			!add(root.display(rt, string(key)), rt.value) {
+			return false
+		}
+
+		for _, e := range rt.edges {
+			next, alive := state, true
+
+			for _, r := range e.label {
+				if next, alive = a.step(next, r); !alive {
+					break
+				}
+			}
+
+			if !alive {
+				continue
+			}
+
+			size := len(key)
+			key = append(key, e.label...)
+
+			if !walk(e.radixTree, next) {
+				return false
+			}
+
+			key = key[:size]
+		}
+
+		return true
+	}
+
+	walk(rt, start)
+
+	return out
+}
@@ -0,0 +1,142 @@
+package goradix
+
+import (
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// matchReference returns keys of the tree matching the pattern.
+func matchReference(rt *Tree[int], pattern string) []string {
+	keys := []string{}
+
+	for key := range rt.All() {
+		if ok, _ := path.Match(pattern, key); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func TestMatch(t *testing.T) {
+	rt := New[int]()
+	for i, key := range []string{
+		"", "rube", "ruber", "rubens", "rubi", "rubicundus", "rubicon",
+		"romane", "romanus", "romulus", "rom*n", "слово", "слон",
+	} {
+		rt.Insert(key, i)
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"rub?c*", []string{"rubicon", "rubicundus"}},
+		{"rom[au]n*", []string{"romane", "romanus"}},
+		{"*us", []string{"romanus", "romulus", "rubicundus"}},
+		{"rube*", []string{"rube", "rubens", "ruber"}},
+		{"rube?", []string{"ruber"}},
+		{"r*b*s", []string{"rubens", "rubicundus"}},
+		{"[^r]*", []string{"слово", "слон"}},
+		{"[!r]*", []string{"слово", "слон"}},
+		{"сл[о-п]?", []string{"слон"}},
+		{"rom\\*n", []string{"rom*n"}},
+		{"", []string{""}},
+		{"*", nil},
+		{"x*", []string{}},
+	}
+
+	for _, tt := range tests {
+		got, err := rt.Match(tt.pattern, 0)
+		if err != nil {
+			t.Fatalf("Match(%q) error = %v", tt.pattern, err)
+		}
+
+		want := tt.want
+		if want == nil {
+			want = matchReference(rt, tt.pattern)
+		}
+
+		if keys := keysOf(got); !reflect.DeepEqual(keys, want) {
+			t.Errorf("Match(%q) = %q; want %q", tt.pattern, keys, want)
+		}
+
+		for _, kv := range got {
+			if v, _ := rt.Get(kv.Key); v != kv.Value {
+				t.Errorf("Match(%q) value of %q = %v; want %v", tt.pattern, kv.Key, kv.Value, v)
+			}
+		}
+	}
+
+	if got, _ := rt.Match("*", 3); !reflect.DeepEqual(keysOf(got), []string{"", "rom*n", "romane"}) {
+		t.Errorf("Match(%q, 3) = %q", "*", keysOf(got))
+	}
+
+	if got, _ := rt.Match("rub*", 2); !reflect.DeepEqual(keysOf(got), []string{"rube", "rubens"}) {
+		t.Errorf("Match(%q, 2) = %q", "rub*", keysOf(got))
+	}
+
+	for _, pattern := range []string{"[", "[]", "[a", "[b-a]", "a\\", "[a-"} {
+		if _, err := rt.Match(pattern, 0); err != ErrBadPattern {
+			t.Errorf("Match(%q) error = %v; want %v", pattern, err, ErrBadPattern)
+		}
+	}
+}
+
+func TestMatchNormalizer(t *testing.T) {
+	rt := normalizedTree()
+
+	got, err := rt.Match("RUB[EÉ]?", 0)
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+
+	if want := []KeyValue[int]{{"Rubén", 3}, {"Ruber", 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Match(%q) = %v; want %v", "RUB[EÉ]?", got, want)
+	}
+}
+
+// globAlphabet makes fuzzed patterns of the fuzz alphabet and glob items.
+var globAlphabet = []string{"a", "b", "é", "*", "?", "[ab]", "[^a]", "[a-é]", "r"}
+
+func FuzzMatch(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2}, []byte{3, 0, 4})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0}, []byte{1, 3, 3, 5, 7})
+
+	f.Fuzz(func(t *testing.T, data []byte, patternData []byte) {
+		rt := New[int]()
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			if op == 3 {
+				rt.Delete(key)
+			} else {
+				rt.Insert(key, value)
+			}
+		})
+
+		var pattern strings.Builder
+		for _, b := range patternData {
+			pattern.WriteString(globAlphabet[int(b)%len(globAlphabet)])
+		}
+
+		got, err := rt.Match(pattern.String(), 0)
+		if err != nil {
+			t.Fatalf("Match(%q) error = %v", pattern.String(), err)
+		}
+
+		want := matchReference(rt, pattern.String())
+		if keys := keysOf(got); !slices.Equal(keys, want) {
+			t.Fatalf("Match(%q) = %q; want %q", pattern.String(), keys, want)
+		}
+
+		if len(want) > 1 {
+			got, _ = rt.Match(pattern.String(), len(want)-1)
+			if keys := keysOf(got); !slices.Equal(keys, want[:len(want)-1]) {
+				t.Fatalf("Match(%q, %d) = %q", pattern.String(), len(want)-1, keys)
+			}
+		}
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/Maxfer4Maxfer/goradix
 
-go 1.15
+go 1.23
@@ -0,0 +1,243 @@
+package goradix
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithGraphemeClusters makes the tree split edges only at boundaries of
+// extended grapheme clusters, so labels never separate a letter from its
+// combining marks or break an emoji sequence. Children are selected by
+// the first grapheme cluster of labels, so prefixes given to lookups and
+// autocomplete queries match whole clusters only: "e" does not complete
+// "é" written as "e" and U+0301. Keys are ordered by clusters in walking
+// and BuildFromSorted.
+//
+// Grapheme clusters are found by simplified rules of Unicode Standard
+// Annex #29: Prepend characters are not recognized and some property
+// sets are approximated by general categories.
+func WithGraphemeClusters[V any]() Option[V] {
+	return func(o *options[V]) {
+		o.graphemes = true
+	}
+}
+
+// units are units edges of a tree are split at: runes or grapheme
+// clusters in grapheme clusters mode. The mode is kept by options of
+// the root, so nodes do not pay for it.
+type units struct {
+	graphemes bool
+}
+
+// units returns units edges of the tree are split at.
+func (rt *Tree[V]) units() units {
+	return units{graphemes: rt.options != nil && rt.options.graphemes}
+}
+
+// first returns the first unit of the given string.
+func (u units) first(s string) string {
+	if u.graphemes {
+		return firstGrapheme(s)
+	}
+
+	return firstRune(s)
+}
+
+// commonPrefix returns common prefix of two strings made of whole units.
+func (u units) commonPrefix(a string, b string) string {
+	if u.graphemes {
+		return graphemeCommonPrefix(a, b)
+	}
+
+	return commonPrefix(a, b)
+}
+
+// compare compares strings by units: bytewise or by grapheme clusters.
+func (u units) compare(a string, b string) int {
+	cPrefix := len(u.commonPrefix(a, b))
+
+	return strings.Compare(u.first(a[cPrefix:]), u.first(b[cPrefix:]))
+}
+
+// hasPrefix reports whether the key starts with the label made of
+// whole units.
+func (u units) hasPrefix(key string, label string) bool {
+	if u.graphemes {
+		return len(graphemeCommonPrefix(key, label)) == len(label)
+	}
+
+	return strings.HasPrefix(key, label)
+}
+
+// graphemeBreak is a Grapheme_Cluster_Break property of a rune.
+type graphemeBreak uint8
+
+const (
+	gbOther graphemeBreak = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+// extendedPictographic approximates the Extended_Pictographic property.
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00A9, 0x00A9, 1}, {0x00AE, 0x00AE, 1}, {0x203C, 0x203C, 1},
+		{0x2049, 0x2049, 1}, {0x2122, 0x2122, 1}, {0x2139, 0x2139, 1},
+		{0x2194, 0x2199, 1}, {0x21A9, 0x21AA, 1}, {0x231A, 0x231B, 1},
+		{0x2328, 0x2328, 1}, {0x2388, 0x2388, 1}, {0x23CF, 0x23CF, 1},
+		{0x23E9, 0x23F3, 1}, {0x23F8, 0x23FA, 1}, {0x24C2, 0x24C2, 1},
+		{0x25AA, 0x25AB, 1}, {0x25B6, 0x25B6, 1}, {0x25C0, 0x25C0, 1},
+		{0x25FB, 0x25FE, 1}, {0x2600, 0x27BF, 1}, {0x2934, 0x2935, 1},
+		{0x2B05, 0x2B07, 1}, {0x2B1B, 0x2B1C, 1}, {0x2B50, 0x2B50, 1},
+		{0x2B55, 0x2B55, 1}, {0x3030, 0x3030, 1}, {0x303D, 0x303D, 1},
+		{0x3297, 0x3297, 1}, {0x3299, 0x3299, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1F000, 0x1F0FF, 1}, {0x1F10D, 0x1F10F, 1}, {0x1F12F, 0x1F12F, 1},
+		{0x1F16C, 0x1F171, 1}, {0x1F17E, 0x1F17F, 1}, {0x1F18E, 0x1F18E, 1},
+		{0x1F191, 0x1F19A, 1}, {0x1F1AD, 0x1F1E5, 1}, {0x1F201, 0x1F20F, 1},
+		{0x1F21A, 0x1F21A, 1}, {0x1F22F, 0x1F22F, 1}, {0x1F232, 0x1F23A, 1},
+		{0x1F23C, 0x1F23F, 1}, {0x1F249, 0x1F3FA, 1}, {0x1F400, 0x1F53D, 1},
+		{0x1F546, 0x1F64F, 1}, {0x1F680, 0x1F6FF, 1}, {0x1F774, 0x1F77F, 1},
+		{0x1F7D5, 0x1F7FF, 1}, {0x1F80C, 0x1F80F, 1}, {0x1F848, 0x1F84F, 1},
+		{0x1F85A, 0x1F85F, 1}, {0x1F888, 0x1F88F, 1}, {0x1F8AE, 0x1F8FF, 1},
+		{0x1F90C, 0x1F93A, 1}, {0x1F93C, 0x1F945, 1}, {0x1F947, 0x1FAFF, 1},
+		{0x1FC00, 0x1FFFD, 1},
+	},
+}
+
+// graphemeBreakOf returns the Grapheme_Cluster_Break property of the rune.
+func graphemeBreakOf(r rune) graphemeBreak {
+	switch {
+	case r < 0x7F && r >= 0x20:
+		return gbOther
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r == 0x200D:
+		return gbZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F,
+		unicode.In(r, unicode.Mn, unicode.Me):
+		return gbExtend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gbRegionalIndicator
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gbT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gbLV
+		}
+
+		return gbLVT
+	}
+
+	return gbOther
+}
+
+// firstGrapheme returns the first extended grapheme cluster of
+// the given string.
+func firstGrapheme(s string) string {
+	if s == "" {
+		return s
+	}
+
+	r, i := utf8.DecodeRuneInString(s)
+	prev := graphemeBreakOf(r)
+
+	// pictographic is true after Extended_Pictographic Extend*,
+	// joined is true after Extended_Pictographic Extend* ZWJ
+	pictographic, joined := unicode.Is(extendedPictographic, r), false
+	regional := prev == gbRegionalIndicator
+
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		next := graphemeBreakOf(r)
+		nextPictographic := unicode.Is(extendedPictographic, r)
+
+		if !graphemeJoins(prev, next, joined && nextPictographic, regional) {
+			break
+		}
+
+		switch {
+		case nextPictographic:
+			pictographic, joined = true, false
+		case next == gbExtend:
+			joined = false
+		case next == gbZWJ:
+			pictographic, joined = false, pictographic
+		default:
+			pictographic, joined = false, false
+		}
+
+		// a pair of regional indicators is a flag
+		regional = false
+		prev = next
+		i += size
+	}
+
+	return s[:i]
+}
+
+// graphemeJoins reports whether there is no grapheme cluster boundary
+// between runes with the given properties.
+func graphemeJoins(prev, next graphemeBreak, emojiZWJ, regional bool) bool {
+	switch {
+	case prev == gbCR && next == gbLF: // GB3
+		return true
+	case prev == gbCR || prev == gbLF || prev == gbControl: // GB4
+		return false
+	case next == gbCR || next == gbLF || next == gbControl: // GB5
+		return false
+	case prev == gbL && (next == gbL || next == gbV || next == gbLV || next == gbLVT): // GB6
+		return true
+	case (prev == gbLV || prev == gbV) && (next == gbV || next == gbT): // GB7
+		return true
+	case (prev == gbLVT || prev == gbT) && next == gbT: // GB8
+		return true
+	case next == gbExtend || next == gbZWJ || next == gbSpacingMark: // GB9, GB9a
+		return true
+	case prev == gbZWJ && emojiZWJ: // GB11
+		return true
+	case prev == gbRegionalIndicator && next == gbRegionalIndicator: // GB12, GB13
+		return regional
+	}
+
+	return false // GB999
+}
+
+// graphemeCommonPrefix returns the longest common prefix of two strings
+// made of whole grapheme clusters. The prefix is a substring of a.
+func graphemeCommonPrefix(a string, b string) string {
+	i := 0
+
+	for i < len(a) && i < len(b) {
+		c := firstGrapheme(a[i:])
+		if c != firstGrapheme(b[i:]) {
+			break
+		}
+
+		i += len(c)
+	}
+
+	return a[:i]
+}
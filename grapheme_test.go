@@ -0,0 +1,299 @@
+package goradix
+
+import (
+	"bytes"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	family      = "👨‍👩‍👧‍👦"
+	familyOfTwo = "👨‍👩‍👧"
+	thumbsUp    = "👍"
+	thumbsUpTan = "👍🏽"
+	flagUS      = "🇺🇸"
+	flagUA      = "🇺🇦"
+	eAcute      = "é"
+	eGrave      = "è"
+)
+
+func TestFirstGrapheme(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", ""},
+		{"ab", "a"},
+		{eAcute + "x", eAcute},
+		{"ẹ́x", "ẹ́"},
+		{family + "a", family},
+		{thumbsUpTan + thumbsUp, thumbsUpTan},
+		{flagUS + flagUA, flagUS},
+		{"🇺🇸🇺", flagUS},
+		{"\r\nx", "\r\n"},
+		{"\n\r", "\n"},
+		{"한글", "한"},
+		{"각x", "각"},
+		{"किx", "कि"},
+		{"́e", "́"},
+	}
+
+	for _, tt := range tests {
+		if got := firstGrapheme(tt.s); got != tt.want {
+			t.Errorf("firstGrapheme(%+q) = %+q; want %+q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGraphemeCommonPrefix(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"ex", "ey", "e"},
+		{eAcute + "x", eGrave + "x", ""},
+		{eAcute + "x", "ex", ""},
+		{"a" + eAcute, "a" + eAcute + "b", "a" + eAcute},
+		{family, familyOfTwo, ""},
+		{thumbsUpTan, thumbsUp, ""},
+		{flagUS + flagUA, flagUS + "🇺", flagUS},
+	}
+
+	for _, tt := range tests {
+		if got := graphemeCommonPrefix(tt.a, tt.b); got != tt.want {
+			t.Errorf("graphemeCommonPrefix(%+q, %+q) = %+q; want %+q",
+				tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// graphemeKeys are keys which share runes but not grapheme clusters.
+var graphemeKeys = []string{
+	"e", "ex", eAcute, eAcute + "x", eGrave, family, familyOfTwo,
+	thumbsUp, thumbsUpTan, flagUS, flagUA, flagUS + flagUA,
+}
+
+func graphemeTree(opts ...Option[int]) *Tree[int] {
+	rt := New(append(opts, WithGraphemeClusters[int]())...)
+
+	for i, key := range graphemeKeys {
+		rt.InsertWithAddSuggestionFunction(key, i, acceptAll[int])
+	}
+
+	return rt
+}
+
+func TestGraphemeClusters(t *testing.T) {
+	rt := graphemeTree()
+
+	checkInvariants(t, rt, true)
+
+	for i, key := range graphemeKeys {
+		if v, ok := rt.Get(key); !ok || v != i {
+			t.Errorf("Get(%+q) = %v, %v; want %v, true", key, v, ok, i)
+		}
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"e", []string{"e", "ex"}},
+		{eAcute, []string{eAcute, eAcute + "x"}},
+		{"👨", nil},
+		{family, []string{family}},
+		{thumbsUp, []string{thumbsUp}},
+		{flagUS, []string{flagUS, flagUS + flagUA}},
+		{"🇺", nil},
+	}
+
+	for _, tt := range tests {
+		got := keysOf(rt.AutoCompleteDepthTraversal(tt.prefix, 0))
+
+		slices.Sort(got)
+		slices.Sort(tt.want)
+
+		if len(got) != len(tt.want) || len(got) != 0 && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AutoCompleteDepthTraversal(%+q) = %+q; want %+q",
+				tt.prefix, got, tt.want)
+		}
+	}
+
+	prefixes := []struct {
+		str  string
+		want []string
+	}{
+		{family, []string{family}},
+		{eAcute + "xy", []string{eAcute, eAcute + "x"}},
+		{"e" + "̣́", []string{}},
+		{flagUS + flagUA, []string{flagUS, flagUS + flagUA}},
+	}
+
+	for _, tt := range prefixes {
+		if got := keysOf(rt.AllPrefixesOf(tt.str)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AllPrefixesOf(%+q) = %+q; want %+q", tt.str, got, tt.want)
+		}
+	}
+
+	// runes mode splits the same keys inside clusters
+	runes := New[int]()
+	for i, key := range graphemeKeys {
+		runes.Insert(key, i)
+	}
+
+	if got := keysOf(runes.AllPrefixesOf(family)); len(got) != 2 {
+		t.Errorf("AllPrefixesOf(%+q) in runes mode = %+q; want 2 keys", family, got)
+	}
+
+	for _, key := range []string{"e", family, flagUS} {
+		if !rt.Delete(key) {
+			t.Errorf("Delete(%+q) = false", key)
+		}
+	}
+
+	checkInvariants(t, rt, true)
+
+	if got := keysOf(rt.AutoCompleteDepthTraversal("e", 0)); !reflect.DeepEqual(got, []string{"ex"}) {
+		t.Errorf("AutoCompleteDepthTraversal(%q) after Delete = %+q", "e", got)
+	}
+}
+
+func TestGraphemeClustersBuildAndSerialize(t *testing.T) {
+	rt := graphemeTree(WithValueCodec[int](intCodec{}))
+
+	keys := slices.SortedFunc(slices.Values(graphemeKeys), rt.compareKeys)
+
+	walked := []string{}
+	for key := range rt.All() {
+		walked = append(walked, key)
+	}
+
+	if !reflect.DeepEqual(walked, keys) {
+		t.Errorf("All() = %+q; want grapheme clusters order %+q", walked, keys)
+	}
+
+	built, err := BuildFromSorted(rt.All(), acceptAll[int], WithGraphemeClusters[int]())
+	if err != nil {
+		t.Fatalf("BuildFromSorted() error = %v", err)
+	}
+
+	checkInvariants(t, built, true)
+
+	if built.StringValues() != rt.StringValues() {
+		t.Errorf("BuildFromSorted() = \n%s\nwant\n%s", built.StringValues(), rt.StringValues())
+	}
+
+	var buf bytes.Buffer
+	if _, err := rt.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+
+	restored := New(WithGraphemeClusters[int](), WithValueCodec[int](intCodec{}))
+	if _, err := restored.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+
+	checkInvariants(t, restored, true)
+
+	buf.Reset()
+
+	if _, err := rt.WriteIndex(&buf); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	mt, err := NewMappedTree(buf.Bytes(),
+		WithGraphemeClusters[int](), WithValueCodec[int](intCodec{}))
+	if err != nil {
+		t.Fatalf("NewMappedTree() error = %v", err)
+	}
+
+	for _, prefix := range []string{"e", eAcute, "🇺", flagUS, "👨"} {
+		want := rt.AutoCompleteDepthTraversal(prefix, 0)
+
+		if got := restored.AutoCompleteDepthTraversal(prefix, 0); !reflect.DeepEqual(got, want) {
+			t.Errorf("restored AutoCompleteDepthTraversal(%+q) = %v; want %v",
+				prefix, got, want)
+		}
+
+		got, err := mt.AutoCompleteDepthTraversal(prefix, 0)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("mapped AutoCompleteDepthTraversal(%+q) = %v, %v; want %v",
+				prefix, got, err, want)
+		}
+	}
+}
+
+// graphemeAlphabet makes fuzzed keys share runes of grapheme clusters.
+var graphemeAlphabet = []string{"e", "́", "👍", "🏽", "‍", "🇺", "x"}
+
+func FuzzGraphemeClusters(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 2, 4, 2, 0, 3, 0, 5, 5, 5, 5, 5, 2, 3, 2, 4})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rt := New(WithGraphemeClusters[int]())
+		model := map[string]int{}
+
+		prefixes := []string{""}
+
+		for i := 0; i+1 < len(data); {
+			op, size := data[i]%3, int(data[i+1]%6)
+			i += 2
+
+			var key strings.Builder
+			for ; size > 0 && i < len(data); size, i = size-1, i+1 {
+				key.WriteString(graphemeAlphabet[int(data[i])%len(graphemeAlphabet)])
+			}
+
+			switch op {
+			case 0, 1:
+				rt.InsertWithAddSuggestionFunction(key.String(), i, acceptAll[int])
+				model[key.String()] = i
+			case 2:
+				rt.Delete(key.String())
+				delete(model, key.String())
+			}
+
+			prefixes = append(prefixes, key.String())
+		}
+
+		checkInvariants(t, rt, true)
+
+		if got := maps.Collect(rt.All()); !maps.Equal(got, model) {
+			t.Fatalf("All() = %v; want %v", got, model)
+		}
+
+		keys := slices.SortedFunc(maps.Keys(model), rt.compareKeys)
+
+		walked := []string{}
+		for key := range rt.All() {
+			walked = append(walked, key)
+		}
+
+		if !slices.Equal(walked, keys) {
+			t.Fatalf("All() = %+q; want %+q", walked, keys)
+		}
+
+		for _, prefix := range prefixes {
+			want := []string{}
+
+			for _, key := range keys {
+				if len(graphemeCommonPrefix(key, prefix)) == len(prefix) {
+					want = append(want, key)
+				}
+			}
+
+			got := []string{}
+			for key := range rt.Prefix(prefix) {
+				got = append(got, key)
+			}
+
+			if !slices.Equal(got, want) {
+				t.Fatalf("Prefix(%+q) = %+q; want %+q", prefix, got, want)
+			}
+		}
+	})
+}
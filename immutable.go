@@ -0,0 +1,501 @@
+package goradix
+
+import (
+	"sort"
+	"strings"
+)
+
+// ImmutableSuggestionFunc is a signature of the functions which
+// determines new suggestion set of an immutable radix tree.
+// The given currentSuggestions belongs to a new version of the tree,
+// so it is safe to change it.
+type ImmutableSuggestionFunc[V any] func(
+	key string, currentSuggestions []KeyValue[V], condidate KeyValue[V],
+) []KeyValue[V]
+
+// inode is a node of an immutable radix tree.
+// Nodes are never changed after a transaction is committed, new versions
+// of the tree share all unchanged nodes with the previous ones.
+type inode[V any] struct {
+	leaf        *KeyValue[V]
+	edges       []iedge[V]
+	suggestions []KeyValue[V]
+}
+
+// iedge represents connection between a node of an immutable radix tree
+// and its child.
+type iedge[V any] struct {
+	label string
+	node  *inode[V]
+}
+
+// Label returns corresponding field of the structure.
+func (e iedge[V]) Label() string {
+	return e.label
+}
+
+// clone returns a shallow copy of the node with its own edges and
+// suggestions slices.
+func (n *inode[V]) clone() *inode[V] {
+	nc := &inode[V]{leaf: n.leaf}
+
+	if len(n.edges) != 0 {
+		nc.edges = make([]iedge[V], len(n.edges))
+		copy(nc.edges, n.edges)
+	}
+
+	if len(n.suggestions) != 0 {
+		nc.suggestions = make([]KeyValue[V], len(n.suggestions))
+		copy(nc.suggestions, n.suggestions)
+	}
+
+	return nc
+}
+
+// addEdge adds the given edge to the node keeping edges sorted by label.
+func (n *inode[V]) addEdge(e iedge[V]) *inode[V] {
+	i := sort.Search(len(n.edges), func(i int) bool {
+		return n.edges[i].label > e.label
+	})
+
+	n.edges = append(n.edges, iedge[V]{})
+	copy(n.edges[i+1:], n.edges[i:])
+	n.edges[i] = e
+
+	return n
+}
+
+// lookup returns the node which exactly matches the given key.
+func (n *inode[V]) lookup(key string) *inode[V] {
+	for key != "" {
+		i := childIndex(n.edges, key)
+		if i == -1 || !strings.HasPrefix(key, n.edges[i].label) {
+			return nil
+		}
+
+		key = key[len(n.edges[i].label):]
+		n = n.edges[i].node
+	}
+
+	return n
+}
+
+// lookupPrefix returns the highest node which subtree holds all keys
+// started with the given prefix.
+func (n *inode[V]) lookupPrefix(prefix string) *inode[V] {
+	for prefix != "" {
+		i := childIndex(n.edges, prefix)
+		if i == -1 {
+			return nil
+		}
+
+		cPrefix := commonPrefix(prefix, n.edges[i].label)
+
+		// cPrefix should meet ether label or prefix
+		if cPrefix != prefix && cPrefix != n.edges[i].label {
+			return nil
+		}
+
+		prefix = strings.TrimPrefix(prefix, cPrefix)
+		n = n.edges[i].node
+	}
+
+	return n
+}
+
+// ImmutableTree is a persistent radix tree. Every change returns a new
+// version of the tree which shares unchanged subtrees with the previous
+// version. Versions are safe for concurrent reads without any locking.
+type ImmutableTree[V any] struct {
+	root *inode[V]
+	size int
+}
+
+// NewImmutableTree creates a new empty immutable radix tree.
+func NewImmutableTree[V any]() *ImmutableTree[V] {
+	return &ImmutableTree[V]{root: &inode[V]{}}
+}
+
+// Len returns the number of keys holded by the tree.
+func (t *ImmutableTree[V]) Len() int {
+	return t.size
+}
+
+// Txn starts a new transaction which batches changes into one new version
+// of the tree.
+func (t *ImmutableTree[V]) Txn() *Txn[V] {
+	return &Txn[V]{
+		root:     t.root,
+		size:     t.size,
+		writable: map[*inode[V]]struct{}{},
+	}
+}
+
+// Insert adds a key-value pair and returns a new version of the tree.
+func (t *ImmutableTree[V]) Insert(key string, value V) *ImmutableTree[V] {
+	txn := t.Txn()
+	txn.Insert(key, value)
+
+	return txn.Commit()
+}
+
+// InsertWithAddSuggestionFunction adds a key-value pair and returns a new
+// version of the tree. Added value will include to a suggestions set of
+// each upper node if ImmutableSuggestionFunc allows it.
+func (t *ImmutableTree[V]) InsertWithAddSuggestionFunction(
+	key string, value V, p ImmutableSuggestionFunc[V],
+) *ImmutableTree[V] {
+	txn := t.Txn()
+	txn.InsertWithAddSuggestionFunction(key, value, p)
+
+	return txn.Commit()
+}
+
+// Delete removes the given key and returns a new version of the tree.
+// It returns false and the same version if the tree does not hold the key.
+func (t *ImmutableTree[V]) Delete(key string) (*ImmutableTree[V], bool) {
+	txn := t.Txn()
+	if !txn.Delete(key) {
+		return t, false
+	}
+
+	return txn.Commit(), true
+}
+
+// Find returns a value associated with the given key.
+func (t *ImmutableTree[V]) Find(key string) V {
+	value, _ := t.Get(key)
+
+	return value
+}
+
+// Get returns a value associated with the given key and reports
+// whether the tree holds the key.
+func (t *ImmutableTree[V]) Get(key string) (value V, ok bool) {
+	n := t.root.lookup(key)
+	if n == nil || n.leaf == nil {
+		return value, false
+	}
+
+	return n.leaf.Value, true
+}
+
+// Has reports whether the tree holds the given key.
+func (t *ImmutableTree[V]) Has(key string) bool {
+	_, ok := t.Get(key)
+
+	return ok
+}
+
+// ClosestSuggestions returns suggestions set stored in the node
+// which prefix is more closest to the given str.
+func (t *ImmutableTree[V]) ClosestSuggestions(str string) []KeyValue[V] {
+	n := t.root.lookupPrefix(str)
+	if n == nil {
+		return []KeyValue[V]{}
+	}
+
+	out := make([]KeyValue[V], len(n.suggestions))
+	copy(out, n.suggestions)
+
+	return out
+}
+
+// AutoCompleteBroadTraversal returns closest node's values to the given str.
+// Tree traversal algorithms is broadly. Non-positive max means no limit.
+func (t *ImmutableTree[V]) AutoCompleteBroadTraversal(
+	str string, max int,
+) []KeyValue[V] {
+	return t.autoCompleteTraversal(str, max, traversalModeBroad)
+}
+
+// AutoCompleteDepthTraversal returns closest node's values to the given str.
+// Tree traversal algorithms is depthly. Non-positive max means no limit.
+func (t *ImmutableTree[V]) AutoCompleteDepthTraversal(
+	str string, max int,
+) []KeyValue[V] {
+	return t.autoCompleteTraversal(str, max, traversalModeDepth)
+}
+
+// autoCompleteTraversal expands each visited node into its closest
+// children holding values like RadixTree does, so both trees return
+// suggestions in the same order.
+func (t *ImmutableTree[V]) autoCompleteTraversal(
+	str string, max int, traversalMode traversalMode,
+) []KeyValue[V] {
+	out := []KeyValue[V]{}
+
+	n := t.root.lookupPrefix(str)
+	if n == nil {
+		return out
+	}
+
+	todo := []*inode[V]{n}
+
+	for len(todo) != 0 && (max <= 0 || len(out) < max) {
+		n, todo = todo[0], todo[1:]
+
+		if n.leaf != nil {
+			out = append(out, *n.leaf)
+		}
+
+		switch traversalMode {
+		case traversalModeBroad:
+			todo = n.childrenWithValue(todo)
+		case traversalModeDepth:
+			todo = append(n.childrenWithValue(nil), todo...)
+		}
+	}
+
+	return out
+}
+
+// childrenWithValue appends the closest children of the node holding
+// values to out.
+func (n *inode[V]) childrenWithValue(out []*inode[V]) []*inode[V] {
+	for i := range n.edges {
+		child := n.edges[i].node
+
+		if child.leaf != nil {
+			out = append(out, child)
+
+			continue
+		}
+
+		out = child.childrenWithValue(out)
+	}
+
+	return out
+}
+
+// Txn is a transaction on an immutable radix tree. Nodes copied during
+// the transaction are changed in place, so a batch of changes copies
+// every node at most once. Txn is not safe for concurrent use.
+type Txn[V any] struct {
+	root     *inode[V]
+	size     int
+	writable map[*inode[V]]struct{}
+}
+
+// Commit returns a new version of the tree with all changes made
+// by the transaction. The transaction can be used further, next changes
+// do not affect the returned version.
+func (txn *Txn[V]) Commit() *ImmutableTree[V] {
+	txn.writable = map[*inode[V]]struct{}{}
+
+	return &ImmutableTree[V]{root: txn.root, size: txn.size}
+}
+
+// Get returns a value associated with the given key and reports
+// whether the transaction holds the key.
+func (txn *Txn[V]) Get(key string) (value V, ok bool) {
+	n := txn.root.lookup(key)
+	if n == nil || n.leaf == nil {
+		return value, false
+	}
+
+	return n.leaf.Value, true
+}
+
+// Insert adds a key-value pair to the transaction.
+func (txn *Txn[V]) Insert(key string, value V) {
+	txn.InsertWithAddSuggestionFunction(key, value, nil)
+}
+
+// InsertWithAddSuggestionFunction adds a key-value pair to the transaction.
+// Added value will include to a suggestions set of each upper node
+// if ImmutableSuggestionFunc allows it.
+func (txn *Txn[V]) InsertWithAddSuggestionFunction(
+	key string, value V, p ImmutableSuggestionFunc[V],
+) {
+	// dublicate value! the previous one leaves suggestions sets first.
+	if _, ok := txn.Get(key); ok {
+		txn.Delete(key)
+	}
+
+	txn.root = txn.insert(txn.root, "", key, KeyValue[V]{key, value}, p)
+	txn.size++
+}
+
+// Delete removes the given key from the transaction.
+// It returns false if the transaction does not hold the key.
+func (txn *Txn[V]) Delete(key string) bool {
+	root, ok := txn.delete(txn.root, key, key)
+	if !ok {
+		return false
+	}
+
+	txn.root = root
+	txn.size--
+
+	return true
+}
+
+// writableNode returns the given node if it has been copied during the
+// transaction, otherwise it returns a copy of the node.
+func (txn *Txn[V]) writableNode(n *inode[V]) *inode[V] {
+	if _, ok := txn.writable[n]; ok {
+		return n
+	}
+
+	nc := n.clone()
+	txn.writable[nc] = struct{}{}
+
+	return nc
+}
+
+// newNode creates a new node owned by the transaction.
+func (txn *Txn[V]) newNode() *inode[V] {
+	n := &inode[V]{}
+	txn.writable[n] = struct{}{}
+
+	return n
+}
+
+// addSuggestion adds the given key-value pair as a suggestion to
+// existed suggestions set of the node.
+func (n *inode[V]) addSuggestion(
+	key string, next KeyValue[V], p ImmutableSuggestionFunc[V],
+) *inode[V] {
+	if p != nil {
+		n.suggestions = p(key, n.suggestions, next)
+	}
+
+	return n
+}
+
+// deleteSuggestion deletes suggestions with the given key from
+// suggestions set of the node.
+func (n *inode[V]) deleteSuggestion(key string) *inode[V] {
+	suggestions := n.suggestions[:0]
+
+	for i := range n.suggestions {
+		if n.suggestions[i].Key != key {
+			suggestions = append(suggestions, n.suggestions[i])
+		}
+	}
+
+	n.suggestions = suggestions
+
+	return n
+}
+
+func (txn *Txn[V]) insert(
+	n *inode[V], upperKey string, key string, kv KeyValue[V],
+	p ImmutableSuggestionFunc[V],
+) *inode[V] {
+	nc := txn.writableNode(n).addSuggestion(upperKey, kv, p)
+
+	if key == "" {
+		nc.leaf = &kv
+
+		return nc
+	}
+
+	// find prefix among the edges
+	if i := childIndex(nc.edges, key); i != -1 {
+		label := nc.edges[i].label
+		cPrefix := commonPrefix(key, label)
+
+		// key: hello  label: he
+		if cPrefix == label {
+			nc.edges[i].node = txn.insert(
+				nc.edges[i].node, upperKey+cPrefix,
+				strings.TrimPrefix(key, cPrefix), kv, p,
+			)
+
+			return nc
+		}
+
+		child := nc.edges[i].node
+		middle := txn.newNode()
+
+		// key: he label: hello
+		if cPrefix == key {
+			middle.leaf = &kv
+			middle.addSuggestion(upperKey+cPrefix, kv, p)
+
+			for j := range child.suggestions {
+				middle.addSuggestion(upperKey+cPrefix, child.suggestions[j], p)
+			}
+
+			middle.edges = []iedge[V]{
+				{label: strings.TrimPrefix(label, cPrefix), node: child},
+			}
+
+			nc.edges[i] = iedge[V]{label: cPrefix, node: middle}
+
+			return nc
+		}
+
+		// key: hello label: head
+		leaf := txn.newNode().addSuggestion(kv.Key, kv, p)
+		leaf.leaf = &kv
+
+		middle.suggestions = append(middle.suggestions, child.suggestions...)
+		middle.addSuggestion(upperKey+cPrefix, kv, p)
+		middle.
+			addEdge(iedge[V]{label: strings.TrimPrefix(label, cPrefix), node: child}).
+			addEdge(iedge[V]{label: strings.TrimPrefix(key, cPrefix), node: leaf})
+
+		nc.edges[i] = iedge[V]{label: cPrefix, node: middle}
+
+		return nc
+	}
+
+	// the string has not been meet before
+	leaf := txn.newNode().addSuggestion(kv.Key, kv, p)
+	leaf.leaf = &kv
+
+	nc.addEdge(iedge[V]{label: key, node: leaf})
+
+	return nc
+}
+
+func (txn *Txn[V]) delete(
+	n *inode[V], key string, fullKey string,
+) (*inode[V], bool) {
+	if key == "" {
+		if n.leaf == nil {
+			return n, false
+		}
+
+		nc := txn.writableNode(n).deleteSuggestion(fullKey)
+		nc.leaf = nil
+
+		return nc, true
+	}
+
+	if i := childIndex(n.edges, key); i != -1 {
+		if !strings.HasPrefix(key, n.edges[i].label) {
+			return n, false
+		}
+
+		child, ok := txn.delete(
+			n.edges[i].node, strings.TrimPrefix(key, n.edges[i].label), fullKey,
+		)
+		if !ok {
+			return n, false
+		}
+
+		nc := txn.writableNode(n).deleteSuggestion(fullKey)
+
+		switch {
+		// the child is empty, drop it
+		case child.leaf == nil && len(child.edges) == 0:
+			nc.edges = append(nc.edges[:i], nc.edges[i+1:]...)
+		// the child has the only child, merge them into one edge
+		case child.leaf == nil && len(child.edges) == 1:
+			nc.edges[i] = iedge[V]{
+				label: nc.edges[i].label + child.edges[0].label,
+				node:  child.edges[0].node,
+			}
+		default:
+			nc.edges[i].node = child
+		}
+
+		return nc, true
+	}
+
+	return n, false
+}
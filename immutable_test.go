@@ -0,0 +1,205 @@
+package goradix
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func acceptAllImmutable[V any](
+	key string, currentSuggestions []KeyValue[V], condidate KeyValue[V],
+) []KeyValue[V] {
+	return append(currentSuggestions, condidate)
+}
+
+func keysOf[V any](kvs []KeyValue[V]) []string {
+	keys := make([]string, len(kvs))
+	for i := range kvs {
+		keys[i] = kvs[i].Key
+	}
+
+	return keys
+}
+
+func TestImmutableTreeVersions(t *testing.T) {
+	v0 := NewImmutableTree[int]()
+	v1 := v0.InsertWithAddSuggestionFunction("rube", 1, acceptAllImmutable[int])
+	v2 := v1.InsertWithAddSuggestionFunction("rubicon", 2, acceptAllImmutable[int])
+	v3, ok := v2.Delete("rube")
+
+	if !ok {
+		t.Fatalf("Delete(%q) = false; want true", "rube")
+	}
+
+	tests := []struct {
+		tree *ImmutableTree[int]
+		keys []string
+	}{
+		{v0, []string{}},
+		{v1, []string{"rube"}},
+		{v2, []string{"rube", "rubicon"}},
+		{v3, []string{"rubicon"}},
+	}
+
+	for i, tt := range tests {
+		if tt.tree.Len() != len(tt.keys) {
+			t.Errorf("v%d.Len() = %d; want %d", i, tt.tree.Len(), len(tt.keys))
+		}
+
+		got := keysOf(tt.tree.ClosestSuggestions("rub"))
+		if !reflect.DeepEqual(got, tt.keys) {
+			t.Errorf("v%d.ClosestSuggestions(%q) = %v; want %v", i, "rub", got, tt.keys)
+		}
+	}
+
+	if _, ok := v3.Delete("rube"); ok {
+		t.Errorf("Delete(%q) of absent key = true; want false", "rube")
+	}
+}
+
+func TestTxn(t *testing.T) {
+	base := NewImmutableTree[int]().Insert("romane", 0)
+
+	txn := base.Txn()
+	keys := []string{"romanus", "romulus", "rubens", "ruber", "rubicon"}
+
+	for i, key := range keys {
+		txn.InsertWithAddSuggestionFunction(key, i+1, acceptAllImmutable[int])
+	}
+
+	txn.Delete("romane")
+
+	next := txn.Commit()
+
+	// changes after commit do not affect the committed version
+	txn.Insert("toast", 6)
+
+	if base.Len() != 1 || !base.Has("romane") || base.Has("romanus") {
+		t.Errorf("Txn changes the base version")
+	}
+
+	if next.Len() != len(keys) || next.Has("romane") || next.Has("toast") {
+		t.Errorf("next.Len() = %d; want %d", next.Len(), len(keys))
+	}
+
+	got := keysOf(next.AutoCompleteDepthTraversal("", 0))
+	if !reflect.DeepEqual(got, keys) {
+		t.Errorf("AutoCompleteDepthTraversal(%q) = %v; want %v", "", got, keys)
+	}
+}
+
+func TestImmutableTreeAutoComplete(t *testing.T) {
+	rt := New[int]()
+	it := NewImmutableTree[int]()
+
+	for i, key := range []string{
+		"ab", "ac", "b", "rube", "rubens", "ruber", "rubi", "rubicon", "rubicundus",
+	} {
+		rt.Insert(key, i)
+		it = it.Insert(key, i)
+	}
+
+	got := keysOf(it.AutoCompleteBroadTraversal("", 3))
+	if want := []string{"ab", "ac", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AutoCompleteBroadTraversal(%q, 3) = %v; want %v", "", got, want)
+	}
+
+	// both trees return suggestions in the same order
+	for _, str := range []string{"", "a", "rub", "rube", "rubic", "x"} {
+		for _, max := range []int{0, 1, 2, 5} {
+			got, want := it.AutoCompleteBroadTraversal(str, max), rt.AutoCompleteBroadTraversal(str, max)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AutoCompleteBroadTraversal(%q, %d) = %v; want %v", str, max, got, want)
+			}
+
+			got, want = it.AutoCompleteDepthTraversal(str, max), rt.AutoCompleteDepthTraversal(str, max)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AutoCompleteDepthTraversal(%q, %d) = %v; want %v", str, max, got, want)
+			}
+		}
+	}
+}
+
+func FuzzImmutableTree(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		it := NewImmutableTree[int]()
+		versions := []*ImmutableTree[int]{it}
+		models := []map[string]int{{}}
+		model := map[string]int{}
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			switch op {
+			case 0, 1:
+				it = it.InsertWithAddSuggestionFunction(key, value, acceptAllImmutable[int])
+				model[key] = value
+			default:
+				_, ok := model[key]
+
+				var deleted bool
+
+				if it, deleted = it.Delete(key); deleted != ok {
+					t.Fatalf("Delete(%q) = %v; want %v", key, deleted, ok)
+				}
+
+				delete(model, key)
+			}
+
+			snapshot := map[string]int{}
+			for k, v := range model {
+				snapshot[k] = v
+			}
+
+			versions = append(versions, it)
+			models = append(models, snapshot)
+		})
+
+		for i, version := range versions {
+			keys := make([]string, 0, len(models[i]))
+			for key := range models[i] {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			got := []string{}
+			for key, value := range version.All() {
+				if value != models[i][key] {
+					t.Fatalf("v%d: All() yields %q: %d; want %d",
+						i, key, value, models[i][key])
+				}
+
+				got = append(got, key)
+			}
+
+			if !reflect.DeepEqual(got, keys) {
+				t.Fatalf("v%d: All() = %v; want %v", i, got, keys)
+			}
+
+			if version.Len() != len(keys) {
+				t.Fatalf("v%d: Len() = %d; want %d", i, version.Len(), len(keys))
+			}
+
+			for _, prefix := range []string{"", "a", "r", "é"} {
+				want := []string{}
+
+				for _, key := range keys {
+					if strings.HasPrefix(key, prefix) {
+						want = append(want, key)
+					}
+				}
+
+				closest := keysOf(version.ClosestSuggestions(prefix))
+				sort.Strings(closest)
+
+				if !reflect.DeepEqual(closest, want) {
+					t.Fatalf("v%d: ClosestSuggestions(%q) = %v; want %v",
+						i, prefix, closest, want)
+				}
+			}
+		}
+	})
+}
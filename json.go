@@ -0,0 +1,432 @@
+package goradix
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ExportFormat is a JSON format of exported trees.
+type ExportFormat int
+
+const (
+	// ExportFlat is a NDJSON stream of {"key": ..., "value": ...} objects,
+	// one object per line in lexicographic order of keys.
+	ExportFlat ExportFormat = iota
+	// ExportNested is a JSON document mirroring edges of the tree. Each node
+	// is an object {"value": ..., "edges": [{"label": ..., "node": ...}]},
+	// "value" is omitted for nodes without values. Original keys kept by
+	// WithKeyNormalizer are exported as "display" fields of nodes.
+	ExportNested
+)
+
+// jsonEntry is a line of the flat format.
+type jsonEntry[V any] struct {
+	Key   string `json:"key"`
+	Value V      `json:"value"`
+}
+
+// Export writes all key-value pairs of the tree to w in the given
+// JSON format. Values are encoded by encoding/json.
+// Suggestions sets are not exported.
+func (rt *Tree[V]) Export(w io.Writer, format ExportFormat) error {
+	bw := bufio.NewWriter(w)
+
+	var err error
+
+	switch format {
+	case ExportFlat:
+		enc := json.NewEncoder(bw)
+		enc.SetEscapeHTML(false)
+
+		rt.Walk(func(key string, value V) bool {
+			err = enc.Encode(jsonEntry[V]{Key: key, Value: value})
+
+			return err == nil
+		})
+	case ExportNested:
+		err = rt.exportNested(rt, bw)
+		if err == nil {
+			err = bw.WriteByte('\n')
+		}
+	default:
+		return fmt.Errorf("goradix: unknown export format %d", format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// exportNested writes the node in the nested format. Original keys are
+// kept by the root.
+func (rt *Tree[V]) exportNested(root *Tree[V], w *bufio.Writer) error {
+	w.WriteByte('{')
+
+	if display, ok := root.displayKey(rt); ok {
+		display, err := json.Marshal(display)
+		if err != nil {
+			return err
+		}
+
+		w.WriteString(`"display":`)
+		w.Write(display)
+		w.WriteByte(',')
+	}
+
+	if rt.hasValue {
+		value, err := json.Marshal(rt.value)
+		if err != nil {
+			return err
+		}
+
+		w.WriteString(`"value":`)
+		w.Write(value)
+
+		if len(rt.edges) != 0 {
+			w.WriteByte(',')
+		}
+	}
+
+	if len(rt.edges) != 0 {
+		w.WriteString(`"edges":[`)
+
+		for i, e := range rt.edges {
+			if i != 0 {
+				w.WriteByte(',')
+			}
+
+			label, err := json.Marshal(e.label)
+			if err != nil {
+				return err
+			}
+
+			w.WriteString(`{"label":`)
+			w.Write(label)
+			w.WriteString(`,"node":`)
+
+			if err := e.radixTree.exportNested(root, w); err != nil {
+				return err
+			}
+
+			w.WriteByte('}')
+		}
+
+		w.WriteByte(']')
+	}
+
+	return w.WriteByte('}')
+}
+
+// BulkLoadError is returned by BulkLoad for malformed input.
+type BulkLoadError struct {
+	// Line is the number of the line where the error occurred,
+	// lines are numbered from 1.
+	Line int
+	Err  error
+}
+
+func (e *BulkLoadError) Error() string {
+	return fmt.Sprintf("goradix: line %d: %v", e.Line, e.Err)
+}
+
+func (e *BulkLoadError) Unwrap() error {
+	return e.Err
+}
+
+// BulkLoad adds key-value pairs read from r to the tree. r holds a stream
+// of the flat or the nested JSON format written by Export, both formats can
+// be mixed. Values are decoded by decodeValue. A malformed input is
+// reported by *BulkLoadError, pairs read before the error stay in the tree.
+func (rt *Tree[V]) BulkLoad(
+	r io.Reader, decodeValue func(data json.RawMessage) (V, error),
+) error {
+	return rt.bulkLoad(r, decodeValue, nil)
+}
+
+// BulkLoadWithAddSuggestionFunction adds key-value pairs read from r to
+// the tree like BulkLoad. Each pair is inserted like
+// InsertWithAddSuggestionFunction does.
+func (rt *Tree[V]) BulkLoadWithAddSuggestionFunction(
+	r io.Reader,
+	decodeValue func(data json.RawMessage) (V, error),
+	p SuggestionFunc[V],
+) error {
+	return rt.bulkLoad(r, decodeValue, p)
+}
+
+// jsonPair is a key-value pair read from the input
+// with the line where the value starts.
+type jsonPair struct {
+	key     string
+	display string
+	value   json.RawMessage
+	line    int
+}
+
+// jsonLoader reads the input of BulkLoad.
+type jsonLoader struct {
+	dec   *json.Decoder
+	lines *lineTracker
+}
+
+func (rt *Tree[V]) bulkLoad(
+	r io.Reader,
+	decodeValue func(data json.RawMessage) (V, error),
+	p SuggestionFunc[V],
+) error {
+	lines := &lineTracker{r: r, line: 1}
+	l := &jsonLoader{dec: json.NewDecoder(lines), lines: lines}
+
+	for l.dec.More() {
+		pairs, err := l.object()
+		if err != nil {
+			return err
+		}
+
+		for _, pair := range pairs {
+			value, err := decodeValue(pair.value)
+			if err != nil {
+				return &BulkLoadError{Line: pair.line, Err: err}
+			}
+
+			key := pair.key
+			if pair.display != "" {
+				key = pair.display
+			}
+
+			rt.insert(key, value, p)
+		}
+	}
+
+	// More stops on EOF or on a stray delimiter
+	if t, err := l.dec.Token(); err == nil {
+		return l.error(fmt.Errorf("unexpected %v", t))
+	} else if err != io.EOF {
+		return l.error(err)
+	}
+
+	return nil
+}
+
+// error wraps err with the current line.
+func (l *jsonLoader) error(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &BulkLoadError{Line: l.lines.lineAt(syntaxErr.Offset), Err: err}
+	}
+
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return &BulkLoadError{Line: l.lines.lineAt(l.dec.InputOffset()), Err: err}
+}
+
+// delim reads the given delimiter.
+func (l *jsonLoader) delim(want json.Delim) error {
+	t, err := l.dec.Token()
+	if err != nil {
+		return l.error(err)
+	}
+
+	if d, ok := t.(json.Delim); !ok || d != want {
+		return l.error(fmt.Errorf("expected %q, got %v", want, t))
+	}
+
+	return nil
+}
+
+// object reads a line of the flat format or a node of the nested one.
+// The node pairs are returned with keys relative to the node.
+func (l *jsonLoader) object() ([]jsonPair, error) {
+	if err := l.delim('{'); err != nil {
+		return nil, err
+	}
+
+	pairs := []jsonPair{}
+	line := l.lines.lineAt(l.dec.InputOffset())
+
+	var key, display *string
+
+	var value *jsonPair
+
+	for l.dec.More() {
+		t, err := l.dec.Token()
+		if err != nil {
+			return nil, l.error(err)
+		}
+
+		switch t {
+		case "key":
+			var k string
+			if err := l.dec.Decode(&k); err != nil {
+				return nil, l.error(err)
+			}
+
+			key = &k
+		case "display":
+			var d string
+			if err := l.dec.Decode(&d); err != nil {
+				return nil, l.error(err)
+			}
+
+			display = &d
+		case "value":
+			value = &jsonPair{line: l.lines.lineAt(l.dec.InputOffset())}
+
+			if err := l.dec.Decode(&value.value); err != nil {
+				return nil, l.error(err)
+			}
+		case "edges":
+			edges, err := l.edges()
+			if err != nil {
+				return nil, err
+			}
+
+			pairs = append(pairs, edges...)
+		default:
+			return nil, l.error(fmt.Errorf("unknown field %v", t))
+		}
+	}
+
+	if err := l.delim('}'); err != nil {
+		return nil, err
+	}
+
+	if key != nil {
+		if value == nil || display != nil || len(pairs) != 0 {
+			return nil, &BulkLoadError{
+				Line: line,
+				Err:  errors.New(`expected "key" and "value" fields only`),
+			}
+		}
+
+		value.key = *key
+
+		return []jsonPair{*value}, nil
+	}
+
+	if display != nil {
+		if value == nil {
+			return nil, &BulkLoadError{
+				Line: line, Err: errors.New(`"display" of a node without value`),
+			}
+		}
+
+		value.display = *display
+	}
+
+	// the node goes before its children like in lexicographic order
+	if value != nil {
+		pairs = append([]jsonPair{*value}, pairs...)
+	}
+
+	return pairs, nil
+}
+
+// edges reads edges of a node of the nested format.
+func (l *jsonLoader) edges() ([]jsonPair, error) {
+	if err := l.delim('['); err != nil {
+		return nil, err
+	}
+
+	pairs := []jsonPair{}
+
+	for l.dec.More() {
+		if err := l.delim('{'); err != nil {
+			return nil, err
+		}
+
+		line := l.lines.lineAt(l.dec.InputOffset())
+
+		var label *string
+
+		var node []jsonPair
+
+		for l.dec.More() {
+			t, err := l.dec.Token()
+			if err != nil {
+				return nil, l.error(err)
+			}
+
+			switch t {
+			case "label":
+				var s string
+				if err := l.dec.Decode(&s); err != nil {
+					return nil, l.error(err)
+				}
+
+				label = &s
+			case "node":
+				if node, err = l.object(); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, l.error(fmt.Errorf("unknown field %v", t))
+			}
+		}
+
+		if err := l.delim('}'); err != nil {
+			return nil, err
+		}
+
+		if label == nil || *label == "" {
+			return nil, &BulkLoadError{
+				Line: line, Err: errors.New(`expected non-empty "label" field`),
+			}
+		}
+
+		for _, pair := range node {
+			pair.key = *label + pair.key
+			pairs = append(pairs, pair)
+		}
+	}
+
+	if err := l.delim(']'); err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+// lineTracker remembers offsets of new lines read from r, so offsets of
+// the json.Decoder can be turned into line numbers. Offsets are asked in
+// nondecreasing order, so passed new lines are forgotten.
+type lineTracker struct {
+	r        io.Reader
+	offset   int64
+	newLines []int64
+	line     int
+}
+
+func (lt *lineTracker) Read(p []byte) (int, error) {
+	n, err := lt.r.Read(p)
+
+	for i, b := range p[:n] {
+		if b == '\n' {
+			lt.newLines = append(lt.newLines, lt.offset+int64(i))
+		}
+	}
+
+	lt.offset += int64(n)
+
+	return n, err
+}
+
+// lineAt returns the number of the line holding the given offset.
+func (lt *lineTracker) lineAt(offset int64) int {
+	passed := sort.Search(len(lt.newLines), func(i int) bool {
+		return lt.newLines[i] >= offset
+	})
+
+	lt.line += passed
+	lt.newLines = lt.newLines[passed:]
+
+	return lt.line
+}
@@ -0,0 +1,124 @@
+package goradix
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func decodeInt(data json.RawMessage) (value int, err error) {
+	err = json.Unmarshal(data, &value)
+
+	return value, err
+}
+
+func TestExportBulkLoad(t *testing.T) {
+	for name, format := range map[string]ExportFormat{
+		"flat":   ExportFlat,
+		"nested": ExportNested,
+	} {
+		t.Run(name, func(t *testing.T) {
+			rt := binaryTree()
+
+			var buf bytes.Buffer
+			if err := rt.Export(&buf, format); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			got := New[int]()
+			if err := got.BulkLoadWithAddSuggestionFunction(
+				&buf, decodeInt, acceptAll[int],
+			); err != nil {
+				t.Fatalf("BulkLoad() error = %v", err)
+			}
+
+			if got.StringValues() != rt.StringValues() {
+				t.Errorf("BulkLoad() = \n%s\nwant\n%s",
+					got.StringValues(), rt.StringValues())
+			}
+
+			checkInvariants(t, got, true)
+		})
+	}
+}
+
+func TestExportFormats(t *testing.T) {
+	rt := New[interface{}]()
+	rt.Insert("ab", 1)
+	rt.Insert("ac", nil)
+
+	for format, want := range map[ExportFormat]string{
+		ExportFlat: `{"key":"ab","value":1}` + "\n" +
+			`{"key":"ac","value":null}` + "\n",
+		ExportNested: `{"edges":[{"label":"a","node":{"edges":[` +
+			`{"label":"b","node":{"value":1}},` +
+			`{"label":"c","node":{"value":null}}]}}]}` + "\n",
+	} {
+		var buf bytes.Buffer
+		if err := rt.Export(&buf, format); err != nil {
+			t.Fatalf("Export(%d) error = %v", format, err)
+		}
+
+		if buf.String() != want {
+			t.Errorf("Export(%d) = %s; want %s", format, buf.String(), want)
+		}
+
+		got := New[interface{}]()
+		if err := got.BulkLoad(&buf, func(data json.RawMessage) (v interface{}, err error) {
+			err = json.Unmarshal(data, &v)
+
+			return v, err
+		}); err != nil {
+			t.Fatalf("BulkLoad(%d) error = %v", format, err)
+		}
+
+		if _, ok := got.Get("ac"); !ok {
+			t.Errorf("BulkLoad(%d) lost a stored nil", format)
+		}
+	}
+}
+
+func TestBulkLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{"syntax", "{\"key\":\"a\",\"value\":1}\n{\"key\":\"b\",\"value\":}\n", 2},
+		{"value", "{\"key\":\"a\",\"value\":1}\n\n{\"key\":\"b\",\"value\":\"x\"}\n", 3},
+		{"unknown field", "{\"key\":\"a\",\"value\":1,\"x\":2}\n", 1},
+		{"no value", "{\"key\":\"a\",\"value\":1}\n{\"key\":\"b\"}\n", 2},
+		{"not object", "{\"key\":\"a\",\"value\":1}\n[]\n", 2},
+		{"stray", "{\"key\":\"a\",\"value\":1}\n}\n", 2},
+		{"truncated", "{\"key\":\"a\",\n\"value\":1", 2},
+		{"empty label", "{\"edges\":[\n{\"label\":\"\",\"node\":{\"value\":1}}]}", 2},
+		{"nested value", "{\"edges\":[\n{\"label\":\"a\",\n\"node\":{\"value\":\"x\"}}]}", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New[int]().BulkLoad(strings.NewReader(tt.input), decodeInt)
+
+			var loadErr *BulkLoadError
+			if !errors.As(err, &loadErr) {
+				t.Fatalf("BulkLoad() error = %v; want *BulkLoadError", err)
+			}
+
+			if loadErr.Line != tt.line {
+				t.Errorf("BulkLoad() error = %v; want line %d", err, tt.line)
+			}
+		})
+	}
+
+	rt := New[int]()
+	if err := rt.BulkLoad(strings.NewReader(""), decodeInt); err != nil {
+		t.Errorf("BulkLoad(empty) error = %v", err)
+	}
+
+	if !reflect.DeepEqual(rt.AutoCompleteDepthTraversal("", 0), []KeyValue[int]{}) {
+		t.Errorf("BulkLoad(empty) added keys")
+	}
+}
@@ -0,0 +1,604 @@
+package goradix
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unsafe"
+)
+
+// The flat index format read by MappedTree. All integers are little-endian.
+//
+//	header:      magic "GRDXIDX", version byte, node count, offsets and
+//	             lengths of the tables below (uint64 each)
+//	nodes:       fixed size records numbered in breadth-first order,
+//	             so children of a node are consecutive records
+//	suggestions: uint32 numbers of suggested nodes
+//	heap:        labels and encoded values, values of nodes with original
+//	             keys kept by WithKeyNormalizer are preceded by the keys
+const (
+	indexMagic   = "GRDXIDX"
+	indexVersion = 1
+
+	indexHeaderSize = 8 + 6*8
+	indexNodeSize   = 56
+)
+
+// offsets of node record fields
+const (
+	nodeParent     = 0
+	nodeFirstChild = 4
+	nodeChildCount = 8
+	nodeFlags      = 12
+	nodeLabelOff   = 16
+	nodeLabelLen   = 24
+	nodeValueLen   = 28
+	nodeValueOff   = 32
+	nodeSuggStart  = 40
+	nodeSuggCount  = 48
+	nodeDisplayLen = 52
+)
+
+// WriteIndex writes the tree in the flat index format served by
+// MappedTree. Values are encoded by the codec set with WithValueCodec.
+func (rt *Tree[V]) WriteIndex(w io.Writer) (int64, error) {
+	codec := rt.valueCodec()
+
+	// nodes are numbered in breadth-first order
+	nodes := []*Tree[V]{rt}
+	index := map[*Tree[V]]uint32{rt: 0}
+	firstChild := []uint32{}
+
+	for i := 0; i < len(nodes); i++ {
+		firstChild = append(firstChild, uint32(len(nodes)))
+
+		for _, e := range nodes[i].edges {
+			if len(nodes) == math.MaxUint32 {
+				return 0, errors.New("goradix: too many nodes for the index")
+			}
+
+			index[e.radixTree] = uint32(len(nodes))
+			nodes = append(nodes, e.radixTree)
+		}
+	}
+
+	records := make([]byte, 0, len(nodes)*indexNodeSize)
+	suggestions := []byte{}
+	heap := []byte{}
+	suggCount := uint64(0)
+
+	for i, node := range nodes {
+		var record [indexNodeSize]byte
+
+		le := binary.LittleEndian
+
+		if node.parent != nil && node != rt {
+			le.PutUint32(record[nodeParent:], index[node.parent.parent])
+			le.PutUint64(record[nodeLabelOff:], uint64(len(heap)))
+			le.PutUint32(record[nodeLabelLen:], uint32(len(node.parent.label)))
+
+			heap = append(heap, node.parent.label...)
+		}
+
+		le.PutUint32(record[nodeFirstChild:], firstChild[i])
+		le.PutUint32(record[nodeChildCount:], uint32(len(node.edges)))
+
+		if node.hasValue {
+			value, err := codec.EncodeValue(node.value)
+			if err != nil {
+				return 0, fmt.Errorf("goradix: encode value: %w", err)
+			}
+
+			if len(value) > math.MaxUint32 {
+				return 0, errors.New("goradix: too long value for the index")
+			}
+
+			le.PutUint32(record[nodeFlags:], uint32(flagHasValue))
+			le.PutUint64(record[nodeValueOff:], uint64(len(heap)))
+			le.PutUint32(record[nodeValueLen:], uint32(len(value)))
+			display, _ := rt.displayKey(node)
+
+			le.PutUint32(record[nodeDisplayLen:], uint32(len(display)))
+
+			heap = append(heap, display...)
+			heap = append(heap, value...)
+		}
+
+		le.PutUint64(record[nodeSuggStart:], suggCount)
+		le.PutUint32(record[nodeSuggCount:], uint32(len(node.suggestions)))
+
+		for _, s := range node.suggestions {
+			j, ok := index[s]
+			if !ok {
+				return 0, errors.New(
+					"goradix: suggestion refers to a node out of the tree")
+			}
+
+			suggestions = le.AppendUint32(suggestions, j)
+			suggCount++
+		}
+
+		records = append(records, record[:]...)
+	}
+
+	header := make([]byte, 0, indexHeaderSize)
+	header = append(header, indexMagic...)
+	header = append(header, indexVersion)
+
+	for _, x := range []uint64{
+		uint64(len(nodes)),
+		indexHeaderSize,
+		indexHeaderSize + uint64(len(records)),
+		suggCount,
+		indexHeaderSize + uint64(len(records)) + uint64(len(suggestions)),
+		uint64(len(heap)),
+	} {
+		header = binary.LittleEndian.AppendUint64(header, x)
+	}
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	for _, b := range [][]byte{header, records, suggestions, heap} {
+		bw.Write(b)
+	}
+
+	err := bw.Flush()
+
+	return cw.n, err
+}
+
+// MappedTree is a read-only radix tree served directly from the flat
+// index written by Tree.WriteIndex. Nodes are read from the index on
+// demand, only returned keys and values are allocated on the heap.
+// MappedTree is safe for concurrent use.
+type MappedTree[V any] struct {
+	nodes       []byte
+	suggestions []byte
+	heap        []byte
+	count       uint32
+	codec       ValueCodec[V]
+	normalize   func(key string) string
+	firstUnit   func(s string) string
+	prefix      func(a string, b string) string
+	close       func() error
+}
+
+// OpenMappedTree maps the index file at the given path into memory.
+// Values are decoded by the codec set with WithValueCodec, queries are
+// normalized by the normalizer set with WithKeyNormalizer. An index of
+// a tree in grapheme clusters mode must be opened with WithGraphemeClusters.
+// The tree must be closed after use.
+func OpenMappedTree[V any](path string, opts ...Option[V]) (*MappedTree[V], error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mt, err := NewMappedTree(data, opts...)
+	if err != nil {
+		unmap()
+
+		return nil, err
+	}
+
+	mt.close = unmap
+
+	return mt, nil
+}
+
+// NewMappedTree creates a read-only radix tree served from the given
+// index data. The data must not be changed while the tree is used.
+func NewMappedTree[V any](data []byte, opts ...Option[V]) (*MappedTree[V], error) {
+	if len(data) < indexHeaderSize ||
+		string(data[:len(indexMagic)]) != indexMagic ||
+		data[len(indexMagic)] != indexVersion {
+		return nil, ErrInvalidFormat
+	}
+
+	header := make([]uint64, 6)
+	for i := range header {
+		header[i] = binary.LittleEndian.Uint64(data[8+8*i:])
+	}
+
+	count, nodesOff, suggOff, suggCount, heapOff, heapLen :=
+		header[0], header[1], header[2], header[3], header[4], header[5]
+
+	size := uint64(len(data))
+
+	if count == 0 || count > math.MaxUint32 ||
+		nodesOff > size || count > (size-nodesOff)/indexNodeSize ||
+		suggOff > size || suggCount > (size-suggOff)/4 ||
+		heapOff > size || heapLen > size-heapOff {
+		return nil, ErrInvalidFormat
+	}
+
+	rt := New[V](opts...)
+
+	return &MappedTree[V]{
+		nodes:       data[nodesOff : nodesOff+count*indexNodeSize],
+		suggestions: data[suggOff : suggOff+suggCount*4],
+		heap:        data[heapOff : heapOff+heapLen],
+		count:       uint32(count),
+		codec:       rt.valueCodec(),
+		normalize:   rt.normalize,
+		firstUnit:   rt.units().first,
+		prefix:      rt.units().commonPrefix,
+		close:       func() error { return nil },
+	}, nil
+}
+
+// Close unmaps the index file. The tree must not be used after Close.
+func (mt *MappedTree[V]) Close() error {
+	return mt.close()
+}
+
+// mnode is a node record of the index.
+type mnode []byte
+
+func (n mnode) uint32(off int) uint32 {
+	return binary.LittleEndian.Uint32(n[off:])
+}
+
+func (n mnode) uint64(off int) uint64 {
+	return binary.LittleEndian.Uint64(n[off:])
+}
+
+func (n mnode) hasValue() bool {
+	return byte(n.uint32(nodeFlags))&flagHasValue != 0
+}
+
+// node returns the record of the node with the given number.
+func (mt *MappedTree[V]) node(i uint32) mnode {
+	if i >= mt.count {
+		panic(ErrInvalidFormat)
+	}
+
+	off := uint64(i) * indexNodeSize
+
+	return mnode(mt.nodes[off : off+indexNodeSize])
+}
+
+// children returns the number of the first child of the node and
+// the count of its children.
+func (mt *MappedTree[V]) children(n mnode) (uint32, uint32) {
+	first, count := n.uint32(nodeFirstChild), n.uint32(nodeChildCount)
+	if first > mt.count || count > mt.count-first {
+		panic(ErrInvalidFormat)
+	}
+
+	return first, count
+}
+
+// heapString returns the given range of the heap as a string.
+// The string refers to the index data, so it must not outlive the tree.
+func (mt *MappedTree[V]) heapString(off uint64, size uint32) string {
+	if off > uint64(len(mt.heap)) || uint64(size) > uint64(len(mt.heap))-off {
+		panic(ErrInvalidFormat)
+	}
+
+	if size == 0 {
+		return ""
+	}
+
+	return unsafe.String(&mt.heap[off], size)
+}
+
+// label returns the label of the edge leading to the node.
+func (mt *MappedTree[V]) label(n mnode) string {
+	return mt.heapString(n.uint64(nodeLabelOff), n.uint32(nodeLabelLen))
+}
+
+// displayKey returns the original key of the node or the empty string.
+func (mt *MappedTree[V]) displayKey(n mnode) string {
+	return mt.heapString(n.uint64(nodeValueOff), n.uint32(nodeDisplayLen))
+}
+
+// display returns the original key of the node which key is the given one.
+// Keys are made of labels which refer to the index data, even
+// concatenation returns a label as is if the other part is empty, so
+// the returned key is always copied to outlive the tree.
+func (mt *MappedTree[V]) display(n mnode, key string) string {
+	if display := mt.displayKey(n); display != "" {
+		return strings.Clone(display)
+	}
+
+	return strings.Clone(key)
+}
+
+// value decodes the value of the node.
+func (mt *MappedTree[V]) value(n mnode) (V, error) {
+	data := mt.heapString(
+		n.uint64(nodeValueOff)+uint64(len(mt.displayKey(n))),
+		n.uint32(nodeValueLen))
+
+	value, err := mt.codec.DecodeValue([]byte(data))
+	if err != nil {
+		return value, fmt.Errorf("goradix: decode value: %w", err)
+	}
+
+	return value, nil
+}
+
+// key returns the key of the node with the given number.
+func (mt *MappedTree[V]) key(i uint32) string {
+	path := []uint32{}
+
+	for depth := uint32(0); i != 0; depth++ {
+		if depth == mt.count {
+			panic(ErrInvalidFormat)
+		}
+
+		path = append(path, i)
+		i = mt.node(i).uint32(nodeParent)
+	}
+
+	var key strings.Builder
+
+	for j := len(path) - 1; j >= 0; j-- {
+		key.WriteString(mt.label(mt.node(path[j])))
+	}
+
+	return key.String()
+}
+
+// child returns the number of the child which label starts with the same
+// rune (or grapheme cluster) as the given key.
+func (mt *MappedTree[V]) child(n mnode, key string) (uint32, bool) {
+	if key == "" {
+		return 0, false
+	}
+
+	r := mt.firstUnit(key)
+	first, count := mt.children(n)
+
+	if count <= linearSearchMaxEdges {
+		for i := first; i < first+count; i++ {
+			if mt.firstUnit(mt.label(mt.node(i))) == r {
+				return i, true
+			}
+		}
+
+		return 0, false
+	}
+
+	lo, hi := first, first+count
+
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		switch c := strings.Compare(mt.firstUnit(mt.label(mt.node(mid))), r); {
+		case c == 0:
+			return mid, true
+		case c < 0:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return 0, false
+}
+
+// lookup returns the number of the node which exactly matches the given key.
+func (mt *MappedTree[V]) lookup(key string) (uint32, bool) {
+	i := uint32(0)
+
+	for key != "" {
+		j, ok := mt.child(mt.node(i), key)
+		if !ok || !strings.HasPrefix(key, mt.label(mt.node(j))) {
+			return 0, false
+		}
+
+		key = key[len(mt.label(mt.node(j))):]
+		i = j
+	}
+
+	return i, true
+}
+
+// lookupPrefix returns the number of the highest node which subtree holds
+// all keys started with the given prefix and the key of the node.
+func (mt *MappedTree[V]) lookupPrefix(prefix string) (uint32, string, bool) {
+	i, rest := uint32(0), prefix
+
+	for rest != "" {
+		j, ok := mt.child(mt.node(i), rest)
+		if !ok {
+			return 0, "", false
+		}
+
+		label := mt.label(mt.node(j))
+		cPrefix := mt.prefix(rest, label)
+
+		// cPrefix should meet ether label or prefix
+		if cPrefix != rest && cPrefix != label {
+			return 0, "", false
+		}
+
+		i = j
+
+		// key: he label: hello
+		if len(cPrefix) != len(label) {
+			return i, prefix[:len(prefix)-len(rest)] + label, true
+		}
+
+		rest = rest[len(cPrefix):]
+	}
+
+	return i, prefix, true
+}
+
+// recoverInvalid turns a panic caused by broken index data into an error.
+func recoverInvalid(err *error) {
+	if r := recover(); r != nil {
+		if r != ErrInvalidFormat {
+			panic(r)
+		}
+
+		*err = ErrInvalidFormat
+	}
+}
+
+// Get returns a value associated with the given key and reports
+// whether the tree holds the key.
+func (mt *MappedTree[V]) Get(key string) (value V, ok bool, err error) {
+	defer recoverInvalid(&err)
+
+	i, ok := mt.lookup(mt.normalize(key))
+	if !ok || !mt.node(i).hasValue() {
+		return value, false, nil
+	}
+
+	value, err = mt.value(mt.node(i))
+
+	return value, err == nil, err
+}
+
+// Find returns a value associated with the given key.
+func (mt *MappedTree[V]) Find(key string) (V, error) {
+	value, _, err := mt.Get(key)
+
+	return value, err
+}
+
+// ClosestSuggestions returns suggestions set stored in the node
+// which prefix is more closest to the given str.
+func (mt *MappedTree[V]) ClosestSuggestions(str string) (out []KeyValue[V], err error) {
+	defer recoverInvalid(&err)
+
+	out = []KeyValue[V]{}
+
+	i, _, ok := mt.lookupPrefix(mt.normalize(str))
+	if !ok {
+		return out, nil
+	}
+
+	n := mt.node(i)
+	start, count := n.uint64(nodeSuggStart), uint64(n.uint32(nodeSuggCount))
+
+	if start > uint64(len(mt.suggestions))/4 ||
+		count > uint64(len(mt.suggestions))/4-start {
+		return nil, ErrInvalidFormat
+	}
+
+	for j := start; j < start+count; j++ {
+		s := binary.LittleEndian.Uint32(mt.suggestions[j*4:])
+
+		value, err := mt.value(mt.node(s))
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, KeyValue[V]{
+			Key:   mt.display(mt.node(s), mt.key(s)),
+			Value: value,
+		})
+	}
+
+	return out, nil
+}
+
+// AutoCompleteBroadTraversal returns closest node's values to the given str.
+// Tree traversal algorithms is broadly. Non-positive max means no limit.
+func (mt *MappedTree[V]) AutoCompleteBroadTraversal(
+	str string, max int,
+) ([]KeyValue[V], error) {
+	return mt.autoCompleteTraversal(str, max, traversalModeBroad)
+}
+
+// AutoCompleteDepthTraversal returns closest node's values to the given str.
+// Tree traversal algorithms is depthly. Non-positive max means no limit.
+func (mt *MappedTree[V]) AutoCompleteDepthTraversal(
+	str string, max int,
+) ([]KeyValue[V], error) {
+	return mt.autoCompleteTraversal(str, max, traversalModeDepth)
+}
+
+// autoCompleteTraversal walks the subtree the same way as
+// Tree.autoCompleteTraversal does.
+func (mt *MappedTree[V]) autoCompleteTraversal(
+	str string, max int, traversalMode traversalMode,
+) (out []KeyValue[V], err error) {
+	defer recoverInvalid(&err)
+
+	// mtree is a node with its key
+	type mtree struct {
+		i   uint32
+		key string
+	}
+
+	out = []KeyValue[V]{}
+
+	i, key, ok := mt.lookupPrefix(mt.normalize(str))
+	if !ok {
+		return out, nil
+	}
+
+	todo := []mtree{{i, key}}
+
+	// every node is visited once at most, unless the index has cycles
+	visited := uint32(0)
+
+	// childrenWithValue adds the closest children holding values to todo.
+	var childrenWithValue func(rt mtree)
+
+	childrenWithValue = func(rt mtree) {
+		if visited++; visited > mt.count {
+			panic(ErrInvalidFormat)
+		}
+
+		first, count := mt.children(mt.node(rt.i))
+
+		for j := first; j < first+count; j++ {
+			child := mtree{j, rt.key + mt.label(mt.node(j))}
+
+			if mt.node(j).hasValue() {
+				todo = append(todo, child)
+
+				continue
+			}
+
+			childrenWithValue(child)
+		}
+	}
+
+	head := 0
+
+	for len(todo) != head && (max <= 0 || len(out) < max) {
+		var rt mtree
+
+		switch traversalMode {
+		case traversalModeBroad:
+			rt, head = todo[head], head+1
+		case traversalModeDepth:
+			rt, todo = todo[len(todo)-1], todo[:len(todo)-1]
+		}
+
+		if n := mt.node(rt.i); n.hasValue() {
+			value, err := mt.value(n)
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, KeyValue[V]{Key: mt.display(n, rt.key), Value: value})
+		}
+
+		n := len(todo)
+
+		childrenWithValue(rt)
+
+		// todo is a stack in depth mode, the first child goes to the top
+		if traversalMode == traversalModeDepth {
+			for i, j := n, len(todo)-1; i < j; i, j = i+1, j-1 {
+				todo[i], todo[j] = todo[j], todo[i]
+			}
+		}
+	}
+
+	return out, nil
+}
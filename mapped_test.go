@@ -0,0 +1,179 @@
+package goradix
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMappedTree(t *testing.T) {
+	rt := binaryTree(WithValueCodec[int](intCodec{}))
+
+	for _, key := range []string{"x", "xylophone", "xyz"} {
+		rt.Insert(key, len(key))
+	}
+
+	// wide node to use binary search over children
+	for r := 'a'; r < 'a'+20; r++ {
+		rt.Insert("w"+string(r), int(r))
+	}
+
+	path := filepath.Join(t.TempDir(), "tree.idx")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rt.WriteIndex(f); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mt, err := OpenMappedTree(path, WithValueCodec[int](intCodec{}))
+	if err != nil {
+		t.Fatalf("OpenMappedTree() error = %v", err)
+	}
+	defer mt.Close()
+
+	keys := []string{
+		"", "r", "rub", "rube", "ruber", "rubi", "rubicu", "rubicon", "rubx",
+		"слово", "сл", "x", "xy", "xyz", "xyzz", "w", "wk", "wz", "z",
+	}
+
+	for _, key := range keys {
+		wantValue, wantOk := rt.Get(key)
+
+		value, ok, err := mt.Get(key)
+		if err != nil || value != wantValue || ok != wantOk {
+			t.Errorf("Get(%q) = %v, %v, %v; want %v, %v",
+				key, value, ok, err, wantValue, wantOk)
+		}
+
+		want := rt.ClosestSuggestions(key)
+		if s, err := mt.ClosestSuggestions(key); err != nil || !reflect.DeepEqual(s, want) {
+			t.Errorf("ClosestSuggestions(%q) = %v, %v; want %v", key, s, err, want)
+		}
+
+		for _, max := range []int{0, 1, 3} {
+			want := rt.AutoCompleteBroadTraversal(key, max)
+			if s, err := mt.AutoCompleteBroadTraversal(key, max); err != nil ||
+				!reflect.DeepEqual(s, want) {
+				t.Errorf("AutoCompleteBroadTraversal(%q, %d) = %v, %v; want %v",
+					key, max, s, err, want)
+			}
+
+			want = rt.AutoCompleteDepthTraversal(key, max)
+			if s, err := mt.AutoCompleteDepthTraversal(key, max); err != nil ||
+				!reflect.DeepEqual(s, want) {
+				t.Errorf("AutoCompleteDepthTraversal(%q, %d) = %v, %v; want %v",
+					key, max, s, err, want)
+			}
+		}
+	}
+}
+
+func TestMappedTreeKeysOutliveClose(t *testing.T) {
+	rt := binaryTree(WithValueCodec[int](intCodec{}))
+	path := filepath.Join(t.TempDir(), "tree.idx")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rt.WriteIndex(f); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mt, err := OpenMappedTree(path, WithValueCodec[int](intCodec{}))
+	if err != nil {
+		t.Fatalf("OpenMappedTree() error = %v", err)
+	}
+
+	got, want := [][]KeyValue[int]{}, [][]KeyValue[int]{}
+
+	// keys of children of the root and prefixes ended inside root edges
+	// are the same strings as labels in the index
+	for _, key := range []string{"", "r", "rub", "сл"} {
+		s, err := mt.ClosestSuggestions(key)
+		if err != nil {
+			t.Fatalf("ClosestSuggestions(%q) error = %v", key, err)
+		}
+
+		got, want = append(got, s), append(want, rt.ClosestSuggestions(key))
+
+		if s, err = mt.AutoCompleteBroadTraversal(key, 0); err != nil {
+			t.Fatalf("AutoCompleteBroadTraversal(%q) error = %v", key, err)
+		}
+
+		got, want = append(got, s), append(want, rt.AutoCompleteBroadTraversal(key, 0))
+
+		if s, err = mt.AutoCompleteDepthTraversal(key, 0); err != nil {
+			t.Fatalf("AutoCompleteDepthTraversal(%q) error = %v", key, err)
+		}
+
+		got, want = append(got, s), append(want, rt.AutoCompleteDepthTraversal(key, 0))
+	}
+
+	if err := mt.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// returned keys are read after the index is unmapped
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results after Close() = %v; want %v", got, want)
+	}
+}
+
+func TestMappedTreeInvalidFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	if _, err := binaryTree().WriteIndex(&buf); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	data := buf.Bytes()
+
+	for _, broken := range [][]byte{
+		nil,
+		data[:indexHeaderSize-1],
+		data[:len(data)-1],
+		append([]byte("GRDXIDX\x02"), data[8:]...),
+	} {
+		if _, err := NewMappedTree[int](broken); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("NewMappedTree(%d bytes) error = %v; want %v",
+				len(broken), err, ErrInvalidFormat)
+		}
+	}
+
+	// a broken child reference is reported by queries
+	broken := append([]byte{}, data...)
+	for i := 0; i < 4; i++ {
+		broken[indexHeaderSize+nodeFirstChild+i] = 0xff
+	}
+
+	mt, err := NewMappedTree[int](broken)
+	if err != nil {
+		t.Fatalf("NewMappedTree() error = %v", err)
+	}
+
+	if _, _, err := mt.Get("rube"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Get() error = %v; want %v", err, ErrInvalidFormat)
+	}
+
+	if _, err := mt.AutoCompleteBroadTraversal("", 0); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("AutoCompleteBroadTraversal() error = %v; want %v",
+			err, ErrInvalidFormat)
+	}
+}
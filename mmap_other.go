@@ -0,0 +1,16 @@
+//go:build !unix
+
+package goradix
+
+import "os"
+
+// mapFile reads the whole file at the given path into memory on platforms
+// without mmap support.
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return nil }, nil
+}
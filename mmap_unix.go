@@ -0,0 +1,36 @@
+//go:build unix
+
+package goradix
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the file at the given path into memory for reading.
+// It returns the mapped data and the function unmapping it.
+func mapFile(path string) ([]byte, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := info.Size()
+	if size == 0 || int64(int(size)) != size {
+		return nil, nil, ErrInvalidFormat
+	}
+
+	data, err := syscall.Mmap(
+		int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
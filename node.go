@@ -0,0 +1,72 @@
+package goradix
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// linearSearchMaxEdges is the maximum count of edges of a node which
+// are scanned linearly during child lookup.
+const linearSearchMaxEdges = 16
+
+// labeled is implemented by edges of radix trees.
+type labeled interface {
+	Label() string
+}
+
+// firstRune returns the first rune of the given string as a string.
+// An invalid UTF-8 byte is returned as is.
+func firstRune(s string) string {
+	_, size := utf8.DecodeRuneInString(s)
+
+	return s[:size]
+}
+
+// childIndex returns the index of the edge which label starts with
+// the same rune as the given key or -1 if there is no such edge.
+// Labels of a node's edges start with different runes and edges are kept
+// sorted, so the child is selected by its first rune only.
+// Like Node4 and Node16 of Adaptive Radix Tree small nodes are scanned
+// linearly, the child of a wide node is found by binary search.
+func childIndex[E labeled](edges []E, key string) int {
+	return childIndexFunc(edges, key, firstRune)
+}
+
+// childIndexFunc is childIndex which compares first units of labels
+// returned by first, like runes or grapheme clusters.
+func childIndexFunc[E labeled](
+	edges []E, key string, first func(string) string,
+) int {
+	if key == "" {
+		return -1
+	}
+
+	r := first(key)
+
+	if len(edges) <= linearSearchMaxEdges {
+		for i := range edges {
+			if first(edges[i].Label()) == r {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	lo, hi := 0, len(edges)
+
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+
+		switch c := strings.Compare(first(edges[mid].Label()), r); {
+		case c == 0:
+			return mid
+		case c < 0:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return -1
+}
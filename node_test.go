@@ -0,0 +1,86 @@
+package goradix
+
+import (
+	"strings"
+	"testing"
+)
+
+// wideTree returns a tree which root has the given count of children
+// started with different CJK runes.
+func wideTree(count int) (*Tree[int], []string) {
+	rt := New[int]()
+	keys := make([]string, count)
+
+	for i := range keys {
+		keys[i] = string(rune(0x4E00+i)) + "字典"
+		rt.Insert(keys[i], i)
+	}
+
+	return rt, keys
+}
+
+// findLinear is the lookup scanning all edges of each node. It is
+// the reference for comparison with the adaptive child lookup.
+func findLinear(rt *Tree[int], key string) (int, bool) {
+	if key == "" {
+		return rt.value, rt.hasValue
+	}
+
+	for i := range rt.edges {
+		cPrefix := commonPrefix(key, rt.edges[i].label)
+
+		if cPrefix == "" {
+			continue
+		}
+
+		if cPrefix == rt.edges[i].label {
+			return findLinear(
+				rt.edges[i].radixTree, strings.TrimPrefix(key, cPrefix))
+		}
+	}
+
+	return 0, false
+}
+
+func TestChildIndex(t *testing.T) {
+	for _, count := range []int{1, linearSearchMaxEdges, 1000} {
+		rt, keys := wideTree(count)
+
+		for i := range keys {
+			if v, ok := rt.Get(keys[i]); !ok || v != i {
+				t.Fatalf("%d children: Get(%q) = %v, %v; want %v, true",
+					count, keys[i], v, ok, i)
+			}
+		}
+
+		if rt.Has("字典") {
+			t.Fatalf("%d children: Has(%q) = true; want false", count, "字典")
+		}
+	}
+}
+
+func BenchmarkFindWideFanOut(b *testing.B) {
+	rt, keys := wideTree(20000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		rt.Find(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkFindWideFanOutLinear(b *testing.B) {
+	rt, keys := wideTree(20000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		findLinear(rt, keys[i%len(keys)])
+	}
+}
+
+func BenchmarkInsertWideFanOut(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		wideTree(5000)
+	}
+}
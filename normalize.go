@@ -0,0 +1,136 @@
+package goradix
+
+import (
+	"strings"
+	"unicode"
+)
+
+// KeyNormalizer maps keys to the form they are stored and searched in.
+// Keys which differ only in ways the normalizer drops are the same key.
+// A normalizer must keep prefixes: the normalized prefix of a key must be
+// a prefix of the normalized key, which is true for per rune mappings.
+type KeyNormalizer func(key string) string
+
+// WithKeyNormalizer makes the tree normalize keys on insert, delete,
+// lookups and all autocomplete queries. Original keys are kept and
+// returned in suggestions, walking and prefix lookups.
+func WithKeyNormalizer[V any](normalizer KeyNormalizer) Option[V] {
+	return func(o *options[V]) {
+		o.normalizer = normalizer
+	}
+}
+
+// ChainKeyNormalizers returns the normalizer applying the given ones
+// in order.
+func ChainKeyNormalizers(normalizers ...KeyNormalizer) KeyNormalizer {
+	return func(key string) string {
+		for _, normalize := range normalizers {
+			key = normalize(key)
+		}
+
+		return key
+	}
+}
+
+// FoldCase is a KeyNormalizer which maps every rune to the lower case
+// form of its Unicode simple case folding orbit, so "Straße", "STRASSE"
+// are not equal, but "ΣΊΣΥΦΟΣ" and "σίσυφος" are.
+func FoldCase(key string) string {
+	return strings.Map(func(r rune) rune {
+		folded := r
+
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			folded = min(folded, f)
+		}
+
+		return unicode.ToLower(folded)
+	}, key)
+}
+
+// StripDiacritics is a KeyNormalizer which removes diacritics from Latin,
+// Greek and Cyrillic letters and drops all nonspacing combining marks,
+// so "rubén" becomes "ruben".
+func StripDiacritics(key string) string {
+	i := strings.IndexFunc(key, func(r rune) bool {
+		_, ok := baseLetters[r]
+
+		return ok || unicode.Is(unicode.Mn, r)
+	})
+	if i == -1 {
+		return key
+	}
+
+	var b strings.Builder
+
+	b.Grow(len(key))
+	b.WriteString(key[:i])
+
+	for _, r := range key[i:] {
+		if base, ok := baseLetters[r]; ok {
+			b.WriteString(base)
+		} else if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// normalize returns the given key in the form stored in the tree.
+func (rt *Tree[V]) normalize(key string) string {
+	if rt.options == nil || rt.options.normalizer == nil {
+		return key
+	}
+
+	return rt.options.normalizer(key)
+}
+
+// display returns the original key of the node of the tree which
+// normalized key is the given one.
+func (rt *Tree[V]) display(node *Tree[V], key string) string {
+	if display, ok := rt.displayKey(node); ok {
+		return display
+	}
+
+	return key
+}
+
+// displayKey returns the original key of the node of the tree if it
+// differs from the normalized one.
+func (rt *Tree[V]) displayKey(node *Tree[V]) (string, bool) {
+	if rt.options == nil {
+		return "", false
+	}
+
+	display, ok := rt.options.displayKeys[node]
+
+	return display, ok
+}
+
+// setDisplayKey keeps the original key of the node of the tree if it
+// differs from the normalized one. Original keys are held by the root,
+// so nodes of trees without normalizer do not pay for them.
+func (rt *Tree[V]) setDisplayKey(node *Tree[V], display string, key string) {
+	if display == key {
+		rt.deleteDisplayKey(node)
+
+		return
+	}
+
+	if rt.options == nil {
+		rt.options = &options[V]{}
+	}
+
+	if rt.options.displayKeys == nil {
+		rt.options.displayKeys = map[*Tree[V]]string{}
+	}
+
+	rt.options.displayKeys[node] = display
+}
+
+// deleteDisplayKey forgets the original key of the node of the tree.
+func (rt *Tree[V]) deleteDisplayKey(node *Tree[V]) {
+	if rt.options != nil {
+		delete(rt.options.displayKeys, node)
+	}
+}
@@ -0,0 +1,160 @@
+package goradix
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestKeyNormalizers(t *testing.T) {
+	tests := []struct {
+		normalizer KeyNormalizer
+		key        string
+		want       string
+	}{
+		{FoldCase, "RuBeN", "ruben"},
+		{FoldCase, "ΣΊΣΥΦΟΣ", "σίσυφοσ"},
+		{FoldCase, "Kelvin", "kelvin"},
+		{StripDiacritics, "rubén", "ruben"},
+		{StripDiacritics, "Ångström", "Angstrom"},
+		{StripDiacritics, "rubén", "ruben"},
+		{StripDiacritics, "ёлка", "елка"},
+		{StripDiacritics, "plain", "plain"},
+		{ChainKeyNormalizers(FoldCase, StripDiacritics), "RUBÉN", "ruben"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.normalizer(tt.key); got != tt.want {
+			t.Errorf("normalize(%q) = %q; want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+// searchNormalizer makes keys case and accent insensitive.
+var searchNormalizer = ChainKeyNormalizers(FoldCase, StripDiacritics)
+
+func normalizedTree() *Tree[int] {
+	rt := New(
+		WithKeyNormalizer[int](searchNormalizer),
+		WithValueCodec[int](intCodec{}),
+	)
+
+	for _, kv := range []KeyValue[int]{
+		{"Rubén", 3}, {"Rubens", 1}, {"Ruber", 4}, {"rubicon", 2},
+	} {
+		rt.InsertWithAddSuggestionFunction(kv.Key, kv.Value, acceptAll[int])
+	}
+
+	return rt
+}
+
+func TestKeyNormalizer(t *testing.T) {
+	rt := normalizedTree()
+
+	for _, key := range []string{"rubens", "RUBENS", "rubéns"} {
+		if v, ok := rt.Get(key); !ok || v != 1 {
+			t.Errorf("Get(%q) = %v, %v; want 1, true", key, v, ok)
+		}
+	}
+
+	want := []KeyValue[int]{{"Rubén", 3}, {"Rubens", 1}, {"Ruber", 4}}
+	if got := rt.AutoCompleteDepthTraversal("RUBE", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("AutoCompleteDepthTraversal() = %v; want %v", got, want)
+	}
+
+	if got := rt.ClosestSuggestions("rubé"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClosestSuggestions() = %v; want %v", got, want)
+	}
+
+	if got := rt.FuzzyAutoComplete("rybe", 1, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("FuzzyAutoComplete() = %v; want %v", got, want)
+	}
+
+	if key, _, _ := rt.LongestPrefix("RUBENSTEIN"); key != "Rubens" {
+		t.Errorf("LongestPrefix() = %q; want %q", key, "Rubens")
+	}
+
+	// the last inserted original key is kept
+	rt.Insert("RUBEN", 5)
+
+	want = []KeyValue[int]{{"RUBEN", 5}, {"Rubens", 1}}
+	if got := rt.AllPrefixesOf("rubens"); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllPrefixesOf() = %v; want %v", got, want)
+	}
+
+	if !rt.Delete("rubén") || rt.Has("Ruben") {
+		t.Errorf("Delete() does not normalize keys")
+	}
+
+	if n := rt.DeletePrefix("RUBE"); n != 2 {
+		t.Errorf("DeletePrefix() = %d; want 2", n)
+	}
+
+	// "rubicon" is stored as is
+	if n := len(rt.options.displayKeys); n != 0 {
+		t.Errorf("tree keeps %d original keys; want 0", n)
+	}
+
+	checkInvariants(t, rt, true)
+}
+
+func TestKeyNormalizerSerialization(t *testing.T) {
+	rt := normalizedTree()
+	want := rt.AutoCompleteDepthTraversal("", 0)
+
+	data, err := rt.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	restored := New(
+		WithKeyNormalizer[int](searchNormalizer), WithValueCodec[int](intCodec{}))
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+
+	if got := restored.AutoCompleteDepthTraversal("", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalBinary() = %v; want %v", got, want)
+	}
+
+	checkInvariants(t, restored, true)
+
+	for _, format := range []ExportFormat{ExportFlat, ExportNested} {
+		var buf bytes.Buffer
+		if err := rt.Export(&buf, format); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+
+		loaded := New(WithKeyNormalizer[int](searchNormalizer))
+		if err := loaded.BulkLoad(&buf, decodeInt); err != nil {
+			t.Fatalf("BulkLoad() error = %v", err)
+		}
+
+		if got := loaded.AutoCompleteDepthTraversal("", 0); !reflect.DeepEqual(got, want) {
+			t.Errorf("BulkLoad(%d) = %v; want %v", format, got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := rt.WriteIndex(&buf); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	mt, err := NewMappedTree(buf.Bytes(),
+		WithKeyNormalizer[int](searchNormalizer), WithValueCodec[int](intCodec{}))
+	if err != nil {
+		t.Fatalf("NewMappedTree() error = %v", err)
+	}
+
+	if got, err := mt.AutoCompleteDepthTraversal("RUB", 0); err != nil ||
+		!reflect.DeepEqual(got, want) {
+		t.Errorf("MappedTree.AutoCompleteDepthTraversal() = %v, %v; want %v",
+			got, err, want)
+	}
+
+	if got, err := mt.ClosestSuggestions("RUB"); err != nil ||
+		!reflect.DeepEqual(got, want) {
+		t.Errorf("MappedTree.ClosestSuggestions() = %v, %v; want %v",
+			got, err, want)
+	}
+}
@@ -0,0 +1,34 @@
+package goradix
+
+// options holds settings of a tree. Only the root node of a tree
+// created by New holds options, all other nodes have nil options.
+type options[V any] struct {
+	codec      ValueCodec[V]
+	topK       *topK[V]
+	normalizer KeyNormalizer
+	graphemes  bool
+	tokenizer  Tokenizer
+	// displayKeys holds original keys of nodes with values which differ
+	// from normalized ones
+	displayKeys map[*Tree[V]]string
+}
+
+// Option configures a tree created by New.
+type Option[V any] func(o *options[V])
+
+// WithValueCodec sets the codec used to serialize values of the tree.
+// GobCodec is used by default.
+func WithValueCodec[V any](codec ValueCodec[V]) Option[V] {
+	return func(o *options[V]) {
+		o.codec = codec
+	}
+}
+
+// valueCodec returns the codec of values of the tree.
+func (rt *Tree[V]) valueCodec() ValueCodec[V] {
+	if rt.options == nil || rt.options.codec == nil {
+		return GobCodec[V]{}
+	}
+
+	return rt.options.codec
+}
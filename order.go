@@ -0,0 +1,169 @@
+package goradix
+
+import "iter"
+
+// Seek returns an iterator over key-value pairs which keys are greater
+// than or equal to the given key in lexicographic order of keys.
+func (rt *Tree[V]) Seek(key string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		rt.seek("", rt.normalize(key), rt.units(), func(node *Tree[V], key string) bool {
+			return yield(rt.display(node, key), node.value)
+		})
+	}
+}
+
+// Range returns an iterator over key-value pairs which keys are greater
+// than or equal to from and less than to in lexicographic order of keys.
+func (rt *Tree[V]) Range(from string, to string) iter.Seq2[string, V] {
+	to = rt.normalize(to)
+
+	return func(yield func(string, V) bool) {
+		rt.seek("", rt.normalize(from), rt.units(), func(node *Tree[V], key string) bool {
+			return rt.compareKeys(key, to) < 0 && yield(rt.display(node, key), node.value)
+		})
+	}
+}
+
+// Ceiling returns the least key of the tree which is greater than or equal
+// to the given key, its value and reports whether such key exists.
+func (rt *Tree[V]) Ceiling(key string) (ceiling string, value V, ok bool) {
+	for k, v := range rt.Seek(key) {
+		return k, v, true
+	}
+
+	return ceiling, value, false
+}
+
+// Floor returns the greatest key of the tree which is less than or equal
+// to the given key, its value and reports whether such key exists.
+func (rt *Tree[V]) Floor(key string) (floor string, value V, ok bool) {
+	node, key := rt.floor("", rt.normalize(key), rt.units())
+	if node == nil {
+		return floor, value, false
+	}
+
+	return rt.display(node, key), node.value, true
+}
+
+// Min returns the least key of the tree, its value and reports whether
+// the tree is not empty.
+func (rt *Tree[V]) Min() (key string, value V, ok bool) {
+	return rt.Ceiling("")
+}
+
+// Max returns the greatest key of the tree, its value and reports whether
+// the tree is not empty.
+func (rt *Tree[V]) Max() (key string, value V, ok bool) {
+	node, k := rt.max("")
+	if node == nil {
+		return key, value, false
+	}
+
+	return rt.display(node, k), node.value, true
+}
+
+// seek calls fn for each node with value of the subtree which key is
+// greater than or equal to the node's key followed by rest in order of keys
+// until fn returns false. It returns false if seeking has been stopped.
+// Keys are compared by the given units of the tree.
+func (rt *Tree[V]) seek(
+	key string, rest string, u units, fn func(node *Tree[V], key string) bool,
+) bool {
+	var visit func(node *Tree[V], key string) bool
+
+	visit = func(node *Tree[V], key string) bool {
+		if node.hasValue && !fn(node, key) {
+			return false
+		}
+
+		for _, e := range node.edges {
+			if !visit(e.radixTree, key+e.label) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	// the key of the node is the sought one, so all keys of the subtree
+	// are greater or equal
+	if rest == "" {
+		return visit(rt, key)
+	}
+
+	// the key of the node is a proper prefix of the sought one, so it is
+	// less
+	for _, e := range rt.edges {
+		cPrefix := len(u.commonPrefix(rest, e.label))
+
+		switch {
+		// key: hello label: he
+		case cPrefix == len(e.label):
+			if !e.radixTree.seek(key+e.label, rest[cPrefix:], u, fn) {
+				return false
+			}
+		// key: he label: hello, or the label is greater at the first
+		// different rune
+		case cPrefix == len(rest) ||
+			u.first(e.label[cPrefix:]) > u.first(rest[cPrefix:]):
+			if !visit(e.radixTree, key+e.label) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// floor returns the node with the greatest key of the subtree which is
+// less than or equal to the node's key followed by rest and its key.
+// Keys are compared by the given units of the tree.
+func (rt *Tree[V]) floor(key string, rest string, u units) (*Tree[V], string) {
+	if rest == "" {
+		if rt.hasValue {
+			return rt, key
+		}
+
+		return nil, ""
+	}
+
+	for i := len(rt.edges) - 1; i >= 0; i-- {
+		e := rt.edges[i]
+		cPrefix := len(u.commonPrefix(rest, e.label))
+
+		switch {
+		// key: hello label: he
+		case cPrefix == len(e.label):
+			if node, k := e.radixTree.floor(key+e.label, rest[cPrefix:], u); node != nil {
+				return node, k
+			}
+		// key: he label: hello, all keys of the subtree are greater
+		case cPrefix == len(rest):
+		case u.first(e.label[cPrefix:]) < u.first(rest[cPrefix:]):
+			return e.radixTree.max(key + e.label)
+		}
+	}
+
+	// the key of the node is a proper prefix of the sought one
+	if rt.hasValue {
+		return rt, key
+	}
+
+	return nil, ""
+}
+
+// max returns the node with the greatest key of the subtree and its key.
+func (rt *Tree[V]) max(key string) (*Tree[V], string) {
+	node := rt
+
+	for len(node.edges) != 0 {
+		e := node.edges[len(node.edges)-1]
+		node, key = e.radixTree, key+e.label
+	}
+
+	if !node.hasValue {
+		return nil, ""
+	}
+
+	return node, key
+}
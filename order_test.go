@@ -0,0 +1,181 @@
+package goradix
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkOrdered compares ordered operations of the tree with ones over
+// sorted keys of the model. cmp compares keys like the tree does.
+func checkOrdered(
+	t *testing.T, rt *Tree[int], model map[string]int, queries []string,
+	cmp func(a, b string) int,
+) {
+	t.Helper()
+
+	keys := slices.SortedFunc(maps.Keys(model), cmp)
+
+	// ceil returns the index of the least key >= query
+	ceil := func(query string) int {
+		i, _ := slices.BinarySearchFunc(keys, query, cmp)
+
+		return i
+	}
+
+	for _, q := range queries {
+		i := ceil(q)
+
+		if got := slices.Collect(seqKeys(rt.Seek(q))); !slices.Equal(got, keys[i:]) {
+			t.Fatalf("Seek(%q) = %q; want %q", q, got, keys[i:])
+		}
+
+		k, v, ok := rt.Ceiling(q)
+		if wantOK := i < len(keys); ok != wantOK || ok && (k != keys[i] || v != model[k]) {
+			t.Fatalf("Ceiling(%q) = %q, %v, %v", q, k, v, ok)
+		}
+
+		j := i
+		if j < len(keys) && keys[j] == q {
+			j++
+		}
+
+		k, v, ok = rt.Floor(q)
+		if wantOK := j > 0; ok != wantOK || ok && (k != keys[j-1] || v != model[k]) {
+			t.Fatalf("Floor(%q) = %q, %v, %v; want %q", q, k, v, ok, keys[:j])
+		}
+
+		for _, to := range queries {
+			want := []string{}
+			if end := ceil(to); end > i {
+				want = keys[i:end]
+			}
+
+			if got := slices.Collect(seqKeys(rt.Range(q, to))); !slices.Equal(got, want) {
+				t.Fatalf("Range(%q, %q) = %q; want %q", q, to, got, want)
+			}
+		}
+	}
+
+	k, _, ok := rt.Min()
+	if ok != (len(keys) != 0) || ok && k != keys[0] {
+		t.Fatalf("Min() = %q, %v; want %q", k, ok, keys)
+	}
+
+	k, _, ok = rt.Max()
+	if ok != (len(keys) != 0) || ok && k != keys[len(keys)-1] {
+		t.Fatalf("Max() = %q, %v; want %q", k, ok, keys)
+	}
+}
+
+// seqKeys returns an iterator over keys of the pairs.
+func seqKeys(seq func(yield func(string, int) bool)) func(yield func(string) bool) {
+	return func(yield func(string) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	model := map[string]int{
+		"rube": 1, "ruber": 2, "rubens": 3, "rubi": 4, "rubicundus": 5,
+		"rubicon": 6, "romane": 7, "romanus": 8, "romulus": 9, "слово": 10, "слон": 11,
+	}
+
+	rt := New[int]()
+	for key, value := range model {
+		rt.Insert(key, value)
+	}
+
+	queries := []string{
+		"", "a", "rom", "roma", "romanus", "romb", "rub", "rube", "rubeo",
+		"rubex", "rubicz", "s", "слов", "слон", "я",
+	}
+
+	checkOrdered(t, rt, model, queries, strings.Compare)
+
+	got := slices.Collect(seqKeys(rt.Range("rom", "rub")))
+	if want := []string{"romane", "romanus", "romulus"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Range(%q, %q) = %q; want %q", "rom", "rub", got, want)
+	}
+
+	if k, v, ok := rt.Floor("rubeo"); k != "rubens" || v != 3 || !ok {
+		t.Errorf("Floor(%q) = %q, %v, %v", "rubeo", k, v, ok)
+	}
+
+	if k, v, ok := rt.Ceiling("rubeo"); k != "ruber" || v != 2 || !ok {
+		t.Errorf("Ceiling(%q) = %q, %v, %v", "rubeo", k, v, ok)
+	}
+
+	// pagination by the next key after the last one
+	page := []string{}
+	for k := range rt.Seek("rube\x00") {
+		if page = append(page, k); len(page) == 2 {
+			break
+		}
+	}
+
+	if want := []string{"rubens", "ruber"}; !reflect.DeepEqual(page, want) {
+		t.Errorf("Seek(%q) page = %q; want %q", "rube\x00", page, want)
+	}
+
+	empty := New[int]()
+	checkOrdered(t, empty, map[string]int{}, queries, strings.Compare)
+
+	empty.Insert("", 1)
+	checkOrdered(t, empty, map[string]int{"": 1}, queries, strings.Compare)
+}
+
+func TestOrderedNormalizerAndGraphemes(t *testing.T) {
+	rt := normalizedTree()
+
+	if k, v, ok := rt.Floor("RUBEO"); k != "Rubens" || v != 1 || !ok {
+		t.Errorf("Floor(%q) = %q, %v, %v", "RUBEO", k, v, ok)
+	}
+
+	got := slices.Collect(seqKeys(rt.Range("RUBÉN", "RUBI")))
+	if want := []string{"Rubén", "Rubens", "Ruber"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Range(%q, %q) = %q; want %q", "RUBÉN", "RUBI", got, want)
+	}
+
+	gt := graphemeTree()
+	model := map[string]int{}
+
+	for i, key := range graphemeKeys {
+		model[key] = i
+	}
+
+	checkOrdered(t, gt, model, append(slices.Clone(graphemeKeys), "", "é", "f", "👨"),
+		gt.compareKeys)
+}
+
+func FuzzOrdered(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rt := New[int]()
+		model := map[string]int{}
+		queries := []string{""}
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			switch op {
+			case 0, 1:
+				rt.Insert(key, value)
+				model[key] = value
+			case 2:
+				rt.Delete(key)
+				delete(model, key)
+			case 3:
+				queries = append(queries, key)
+			}
+		})
+
+		checkOrdered(t, rt, model, queries, strings.Compare)
+	})
+}
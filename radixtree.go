@@ -2,61 +2,62 @@ package goradix
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"unicode/utf8"
 )
 
 // Edge represents connection between a parent node of 
 // a radix tree and its child.
-type edge struct {
+type edge[V any] struct {
 	label     string
-	radixTree *RadixTree
-	parent    *RadixTree
+	radixTree *Tree[V]
+	parent    *Tree[V]
 }
 
 // NewEdge creates a new empty edge.
-func newEdge() *edge {
-	return &edge{}
+func newEdge[V any]() *edge[V] {
+	return &edge[V]{}
 }
 
 // SetParent returns a corresponding field of the structure.
-func (e *edge) SetParent(parent *RadixTree) *edge {
+func (e *edge[V]) SetParent(parent *Tree[V]) *edge[V] {
 	e.parent = parent
 
 	return e
 }
 
 // Label returns corresponding field of the structure.
-func (e *edge) Label() string {
+func (e *edge[V]) Label() string {
 	return e.label
 }
 
 // SetLabel sets corresponding field of the structure.
-func (e *edge) SetLabel(label string) *edge {
+func (e *edge[V]) SetLabel(label string) *edge[V] {
 	e.label = label
 
 	return e
 }
 
 // RadixTree returns corresponding field of the structure.
-func (e *edge) RadixTree() *RadixTree {
+func (e *edge[V]) RadixTree() *Tree[V] {
 	return e.radixTree
 }
 
 // SetRadixTree sets corresponding field of the structure.
-func (e *edge) SetRadixTree(radixTree *RadixTree) *edge {
+func (e *edge[V]) SetRadixTree(radixTree *Tree[V]) *edge[V] {
 	e.radixTree = radixTree
 
 	return e
 }
 
 // String returs a string representation of the edge.
-func (e *edge) String() string {
+func (e *edge[V]) String() string {
 	return "'" + e.label + "'" + "addr:" + fmt.Sprintf("%p", e)
 }
 
-func (e *edge) stringValues(tabLabel string, tabRadixTree string) string {
-	if e.radixTree.value != nil {
+func (e *edge[V]) stringValues(tabLabel string, tabRadixTree string) string {
+	if e.radixTree.hasValue {
 		return fmt.Sprintf("%s'%s' (value: %v)\n%s",
 			tabLabel,
 			e.label,
@@ -70,7 +71,7 @@ func (e *edge) stringValues(tabLabel string, tabRadixTree string) string {
 		e.radixTree.stringValues(tabRadixTree+"  "))
 }
 
-func (e *edge) stringSuggestions(tabLabel string, tabRadixTree string) string {
+func (e *edge[V]) stringSuggestions(tabLabel string, tabRadixTree string) string {
 	return fmt.Sprintf("%s'%s' (value: %v, addr: %s, suggestions: %v)\n%s",
 		tabLabel,
 		e.label,
@@ -80,7 +81,7 @@ func (e *edge) stringSuggestions(tabLabel string, tabRadixTree string) string {
 		e.radixTree.stringSuggestions(tabRadixTree+"  "))
 }
 
-func (e *edge) stringParentChild(tabLabel string, tabRadixTree string) string {
+func (e *edge[V]) stringParentChild(tabLabel string, tabRadixTree string) string {
 	return fmt.Sprintf("%s'%s' parent: %s addr: %s"+
 		" (parent: %s, addr: %s, value: %v)\n%s",
 		tabLabel,
@@ -93,23 +94,33 @@ func (e *edge) stringParentChild(tabLabel string, tabRadixTree string) string {
 		e.radixTree.stringParentChild(tabRadixTree+"  "))
 }
 
-// RadixTree is a data structure for compact storing strings and values associated
+// Tree is a data structure for compact storing strings and values associated
 // with each string.
-type RadixTree struct {
-	parent      *edge
-	value       interface{}
-	edges       []*edge
-	suggestions []*RadixTree
+type Tree[V any] struct {
+	parent      *edge[V]
+	value       V
+	hasValue    bool
+	edges       []*edge[V]
+	suggestions []*Tree[V]
+	options     *options[V]
 }
 
-// NewRadixTree creates a new empty radix tree.
-func NewRadixTree() *RadixTree {
-	rt := &RadixTree{}
+// New creates a new empty radix tree holding values of type V.
+func New[V any](opts ...Option[V]) *Tree[V] {
+	rt := &Tree[V]{}
+
+	if len(opts) != 0 {
+		rt.options = &options[V]{}
+
+		for _, opt := range opts {
+			opt(rt.options)
+		}
+	}
 
 	return rt
 }
 
-func (rt *RadixTree) stringSuggestions(tab string) (out string) {
+func (rt *Tree[V]) stringSuggestions(tab string) (out string) {
 	for i := range rt.edges {
 		tabLabel := tab + "├──"
 		tabRadixTree := tab + "│  "
@@ -125,7 +136,7 @@ func (rt *RadixTree) stringSuggestions(tab string) (out string) {
 	return out
 }
 
-func (rt *RadixTree) stringParentChild(tab string) (out string) {
+func (rt *Tree[V]) stringParentChild(tab string) (out string) {
 	for i := range rt.edges {
 		tabLabel := tab + "├──"
 		tabRadixTree := tab + "│  "
@@ -141,7 +152,7 @@ func (rt *RadixTree) stringParentChild(tab string) (out string) {
 	return out
 }
 
-func (rt *RadixTree) stringValues(tab string) (out string) {
+func (rt *Tree[V]) stringValues(tab string) (out string) {
 	for i := range rt.edges {
 		tabLabel := tab + "├──"
 		tabRadixTree := tab + "│  "
@@ -159,57 +170,86 @@ func (rt *RadixTree) stringValues(tab string) (out string) {
 
 // StringParentChild returs a string representation of the radix tree.
 // It aims to show parent-child relationships inside the tree.
-func (rt *RadixTree) StringParentChild() string {
+func (rt *Tree[V]) StringParentChild() string {
 	return fmt.Sprintf(". addr: %s \n%s", rt, rt.stringParentChild(""))
 }
 
 // StringSuggestions returs a string representation of the radix tree.
 // It aims to shot suggestions sets accosiated with each node of the tree.
-func (rt *RadixTree) StringSuggestions() string {
+func (rt *Tree[V]) StringSuggestions() string {
 	return fmt.Sprintf(". addr: %s suggs: %v\n%s",
 		rt, rt.suggestions, rt.stringSuggestions(""))
 }
 
 // StringValues returs a string representation of the radix tree.
 // Is aim to show the radix tree and holded values.
-func (rt *RadixTree) StringValues() string {
+func (rt *Tree[V]) StringValues() string {
 	return fmt.Sprintf(". \n%s", rt.stringValues(""))
 }
 
 // String returs a basic string representation of the radix tree.
-func (rt *RadixTree) String() string {
+func (rt *Tree[V]) String() string {
 	return fmt.Sprintf("%p", rt)[8:]
 }
 
 // Value returns corresponding value assosiated with rt.
-func (rt *RadixTree) Value() interface{} {
+func (rt *Tree[V]) Value() V {
 	return rt.value
 }
 
 // setValue sets corresponding field of the structure.
-func (rt *RadixTree) setValue(value interface{}) *RadixTree {
+func (rt *Tree[V]) setValue(value V) *Tree[V] {
 	rt.value = value
+	rt.hasValue = true
 
 	return rt
 }
 
 // setParent sets corresponding field of the structure.
-func (rt *RadixTree) setParent(parent *edge) *RadixTree {
+func (rt *Tree[V]) setParent(parent *edge[V]) *Tree[V] {
 	rt.parent = parent
 
 	return rt
 }
 
 // setEdges sets corresponding field of the structure.
-func (rt *RadixTree) setEdges(edges []*edge) *RadixTree {
+func (rt *Tree[V]) setEdges(edges []*edge[V]) *Tree[V] {
 	rt.edges = edges
 
 	return rt
 }
 
+// addEdge adds the given edge to the node keeping edges sorted by label.
+// Labels of a node's edges start with different runes, so the order of
+// edges is the lexicographic order of keys. In grapheme clusters mode
+// edges are sorted by first clusters of labels.
+func (rt *Tree[V]) addEdge(e *edge[V], u units) *Tree[V] {
+	first := u.first(e.label)
+
+	i := sort.Search(len(rt.edges), func(i int) bool {
+		return u.first(rt.edges[i].label) > first
+	})
+
+	rt.edges = append(rt.edges, nil)
+	copy(rt.edges[i+1:], rt.edges[i:])
+	rt.edges[i] = e
+
+	return rt
+}
+
+// childIndex returns the index of the edge which label starts with
+// the same unit as the given key or -1 if there is no such edge.
+func (rt *Tree[V]) childIndex(key string, u units) int {
+	if !u.graphemes {
+		return childIndex(rt.edges, key)
+	}
+
+	return childIndexFunc(rt.edges, key, firstGrapheme)
+}
+
 // setSuggestions sets corresponding field of the structure.
-func (rt *RadixTree) setSuggestions(s []*RadixTree) *RadixTree {
-	c := make([]*RadixTree, len(s))
+func (rt *Tree[V]) setSuggestions(s []*Tree[V]) *Tree[V] {
+	c := make([]*Tree[V], len(s))
 
 	copy(c, s)
 
@@ -218,17 +258,17 @@ func (rt *RadixTree) setSuggestions(s []*RadixTree) *RadixTree {
 	return rt
 }
 
-// AddSuggestionFunction is a signature of the functions which
+// SuggestionFunc is a signature of the functions which
 // determines new suggestion set.
-type AddSuggestionFunction func(
-	key string, currentSuggestions []*RadixTree, condidate *RadixTree,
-) []*RadixTree
+type SuggestionFunc[V any] func(
+	key string, currentSuggestions []*Tree[V], condidate *Tree[V],
+) []*Tree[V]
 
 // addSuggestion adds the given RadixTree as a suggestion to existed
 // suggestions set.
-func (rt *RadixTree) addSuggestion(
-	key string, next *RadixTree, addSuggestionFunction AddSuggestionFunction,
-) *RadixTree {
+func (rt *Tree[V]) addSuggestion(
+	key string, next *Tree[V], addSuggestionFunction SuggestionFunc[V],
+) *Tree[V] {
 	if addSuggestionFunction != nil {
 		rt.suggestions = addSuggestionFunction(key, rt.suggestions, next)
 	}
@@ -236,11 +276,11 @@ func (rt *RadixTree) addSuggestion(
 	return rt
 }
 
-// addSuggestions adds the given []*RadixTree as a suggestions to existed
+// addSuggestions adds the given []*Tree[V] as a suggestions to existed
 // suggestions set.
-func (rt *RadixTree) addSuggestions(
-	key string, next []*RadixTree, addSuggestionFunction AddSuggestionFunction,
-) *RadixTree {
+func (rt *Tree[V]) addSuggestions(
+	key string, next []*Tree[V], addSuggestionFunction SuggestionFunc[V],
+) *Tree[V] {
 	for i := range next {
 		rt.addSuggestion(key, next[i], addSuggestionFunction)
 	}
@@ -249,7 +289,7 @@ func (rt *RadixTree) addSuggestions(
 }
 
 // deleteSuggestion deletes suggestions from suggestion set of the current node.
-func (rt *RadixTree) deleteSuggestion(s *RadixTree) *RadixTree {
+func (rt *Tree[V]) deleteSuggestion(s *Tree[V]) *Tree[V] {
 	for i := range rt.suggestions {
 		if rt.suggestions[i] == s {
 			rt.suggestions = append(
@@ -263,12 +303,26 @@ func (rt *RadixTree) deleteSuggestion(s *RadixTree) *RadixTree {
 	return rt
 }
 
+// replaceSuggestion replaces the suggestion s with the suggestion by
+// in suggestion set of the current node.
+func (rt *Tree[V]) replaceSuggestion(s *Tree[V], by *Tree[V]) *Tree[V] {
+	for i := range rt.suggestions {
+		if rt.suggestions[i] == s {
+			rt.suggestions[i] = by
+
+			return rt
+		}
+	}
+
+	return rt
+}
+
 // NodeWithValueCount returns total count of nodes which holding values.
-func (rt *RadixTree) NodeWithValueCount() int {
-	var deepDive func(rt *RadixTree, count int) int
+func (rt *Tree[V]) NodeWithValueCount() int {
+	var deepDive func(rt *Tree[V], count int) int
 
-	deepDive = func(rt *RadixTree, count int) int {
-		if rt.value != nil {
+	deepDive = func(rt *Tree[V], count int) int {
+		if rt.hasValue {
 			count++
 		}
 
@@ -284,13 +338,13 @@ func (rt *RadixTree) NodeWithValueCount() int {
 
 // NodeWithValueCountByCounter returns total count of nodes which holding values.
 // The incoming counter desided how many should be add to a result count.
-func (rt *RadixTree) NodeWithValueCountByCounter(
-	counter func(interface{}) int,
+func (rt *Tree[V]) NodeWithValueCountByCounter(
+	counter func(V) int,
 ) int {
-	var deepDive func(rt *RadixTree, count int) int
+	var deepDive func(rt *Tree[V], count int) int
 
-	deepDive = func(rt *RadixTree, count int) int {
-		if rt.value != nil {
+	deepDive = func(rt *Tree[V], count int) int {
+		if rt.hasValue {
 			count += counter(rt.value)
 		}
 
@@ -305,90 +359,185 @@ func (rt *RadixTree) NodeWithValueCountByCounter(
 }
 
 // commonPrefix is a helper function returns common prefix of two strings.
+// Strings are compared bytewise, the prefix is backed off to a rune
+// boundary only if the strings differ inside a multibyte rune.
+// The prefix is a substring of a, so nothing is allocated.
 func commonPrefix(a string, b string) string {
-	prefix := ""
-
-	runeCount := utf8.RuneCountInString(a)
-	if runeCount > utf8.RuneCountInString(b) {
-		runeCount = utf8.RuneCountInString(b)
+	n := len(a)
+	if n > len(b) {
+		n = len(b)
 	}
 
-	for i := 0; i < runeCount; i++ {
-		runeA, _ := utf8.DecodeRuneInString(a[len(prefix):])
-		runeB, _ := utf8.DecodeRuneInString(b[len(prefix):])
-
-		if runeA != runeB {
-			break
-		}
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
 
-		prefix += string(runeA)
+	for i > 0 && (i < len(a) && !utf8.RuneStart(a[i]) ||
+		i < len(b) && !utf8.RuneStart(b[i])) {
+		i--
 	}
 
-	return prefix
+	return a[:i]
 }
 
 // Insert adds a key-value pair to the tree.
-func (rt *RadixTree) Insert(key string, value interface{}) {
+func (rt *Tree[V]) Insert(key string, value V) {
 	rt.insert(key, value, nil)
 }
 
 // InsertWithAddSuggestionFunction add a key-pair to the tree.
 // Added value will include to a suggestions set of each upper node.
-// Before add to a suggestion set AddSuggestionFunction will say can a value
+// Before add to a suggestion set SuggestionFunc will say can a value
 // be added to the set.
-func (rt *RadixTree) InsertWithAddSuggestionFunction(
-	key string, value interface{}, p AddSuggestionFunction,
+// If the tree already holds the key SuggestionFunc decides again about
+// the node with the new value: the node is deleted from each set and
+// offered to it, so a set which appends accepted nodes moves the node to
+// its end. Without SuggestionFunc the sets are not changed.
+func (rt *Tree[V]) InsertWithAddSuggestionFunction(
+	key string, value V, p SuggestionFunc[V],
 ) {
 	rt.insert(key, value, p)
 }
 
+// insert adds a key-value pair to the tree. The key is normalized and
+// the original one is kept in the node. The top-K policy of the tree
+// takes place of the given SuggestionFunc.
+func (rt *Tree[V]) insert(
+	key string, value V, addSuggestionFunction SuggestionFunc[V],
+) {
+	display := key
+	key = rt.normalize(key)
+	t := rt.topKPolicy()
+
+	// dublicate value! the node is re-evaluated with the new value
+	if node := rt.lookup(key); node != nil && node.hasValue {
+		old := node.value
+		node.setValue(value)
+
+		switch {
+		case t != nil:
+			t.updated(node, old)
+		case addSuggestionFunction != nil:
+			rt.reoffer(node, key, addSuggestionFunction)
+		}
+	} else if t == nil {
+		rt.insertNode(key, value, addSuggestionFunction)
+	} else {
+		rt.insertNode(key, value, nil)
+
+		t.inserted(rt.lookup(key))
+	}
+
+	if rt.options != nil && rt.options.normalizer != nil {
+		rt.setDisplayKey(rt.lookup(key), display, key)
+	}
+}
+
+// reoffer re-evaluates the node with the given key in suggestions sets of
+// the node and all upper nodes up to the current one: the node is deleted
+// from each set and the SuggestionFunc decides again can it be added.
+func (rt *Tree[V]) reoffer(
+	node *Tree[V], key string, addSuggestionFunction SuggestionFunc[V],
+) {
+	for upper := node; ; upper = upper.upper() {
+		upper.deleteSuggestion(node).
+			addSuggestion(key, node, addSuggestionFunction)
+
+		if upper == rt {
+			return
+		}
+
+		key = key[:len(key)-len(upper.parent.label)]
+	}
+}
+
+// UpdateScore changes the value associated with the given key.
+// Suggestions sets of the node and all upper nodes are re-ranked
+// according to the top-K policy of the tree if any: the node moves
+// within the sets, gets into them or is evicted with the best other
+// node of the subtree taking its place.
+// Trees without the top-K policy have no SuggestionFunc to re-evaluate
+// the sets, so only the value is changed there; use
+// InsertWithAddSuggestionFunction to re-evaluate the sets of such trees.
+// It returns false if the tree does not hold the key.
+func (rt *Tree[V]) UpdateScore(key string, value V) bool {
+	node := rt.lookup(rt.normalize(key))
+	if node == nil || !node.hasValue {
+		return false
+	}
+
+	old := node.value
+	node.setValue(value)
+
+	if t := rt.topKPolicy(); t != nil {
+		t.updated(node, old)
+	}
+
+	return true
+}
+
 // nolint: funlen
 // linter: style with internal helper function for recursion call makes
 // it not rational to split the next procedure to into parts.
-func (rt *RadixTree) insert(
-	key string, value interface{}, addSuggestionFunction AddSuggestionFunction,
+func (rt *Tree[V]) insertNode(
+	key string, value V, addSuggestionFunction SuggestionFunc[V],
 ) {
-	var deleteSuggestion func(rt *RadixTree, sug *RadixTree, key string)
+	u := rt.units()
 
-	deleteSuggestion = func(rt *RadixTree, sug *RadixTree, key string) {
-		rt.deleteSuggestion(sug)
+	var replaceSuggestion func(rt *Tree[V], sug *Tree[V], by *Tree[V], key string)
 
-		// find prefix among the edges
-		for i := range rt.edges {
-			cPrefix := commonPrefix(key, rt.edges[i].label)
+	replaceSuggestion = func(rt *Tree[V], sug *Tree[V], by *Tree[V], key string) {
+		if by == nil {
+			rt.deleteSuggestion(sug)
+		} else {
+			rt.replaceSuggestion(sug, by)
+		}
 
-			// key and label are completly different
-			if cPrefix == "" {
-				continue
-			}
+		// find prefix among the edges
+		if i := rt.childIndex(key, u); i != -1 {
+			cPrefix := u.commonPrefix(key, rt.edges[i].label)
 
 			// key: hello  label: he
 			if cPrefix == rt.edges[i].label {
-				deleteSuggestion(rt.edges[i].radixTree,
-					sug, strings.TrimPrefix(key, cPrefix))
+				replaceSuggestion(rt.edges[i].radixTree,
+					sug, by, strings.TrimPrefix(key, cPrefix))
 
 				return
 			}
 		}
 	}
 
-	deleteLastAdded := func(dl *RadixTree) {
-		deleteSuggestion(rt, dl, key)
+	deleteLastAdded := func(dl *Tree[V]) {
+		replaceSuggestion(rt, dl, nil, key)
+	}
+
+	replaceLastAdded := func(dl *Tree[V], by *Tree[V]) {
+		replaceSuggestion(rt, dl, by, key)
 	}
 
 	var insert func(
-		rt *RadixTree,
+		rt *Tree[V],
 		upperKey string,
 		key string,
-		income *RadixTree,
+		income *Tree[V],
 	)
 
-	insert = func(rt *RadixTree, upperKey string, key string, income *RadixTree) {
+	insert = func(rt *Tree[V], upperKey string, key string, income *Tree[V]) {
 		rt.addSuggestion(upperKey, income, addSuggestionFunction)
 
 		// dublicate value! overwrite!
 		if key == "" {
-			rt.value = income.value
+			// the node without value takes place of income in suggestions
+			if !rt.hasValue {
+				rt.setValue(income.value)
+
+				replaceLastAdded(income, rt)
+
+				return
+			}
+
+			rt.setValue(income.value)
 
 			deleteLastAdded(income)
 
@@ -396,13 +545,8 @@ func (rt *RadixTree) insert(
 		}
 
 		// find prefix among the edges
-		for i := range rt.edges {
-			cPrefix := commonPrefix(key, rt.edges[i].label)
-
-			// key and label are completly different
-			if cPrefix == "" {
-				continue
-			}
+		if i := rt.childIndex(key, u); i != -1 {
+			cPrefix := u.commonPrefix(key, rt.edges[i].label)
 
 			// key: hello  label: he
 			if cPrefix == rt.edges[i].label {
@@ -418,7 +562,7 @@ func (rt *RadixTree) insert(
 
 			// key: he label: hello
 			if strings.TrimPrefix(key, cPrefix) == "" {
-				nedge := newEdge().
+				nedge := newEdge[V]().
 					SetLabel(strings.TrimPrefix(rt.edges[i].label, cPrefix)).
 					SetRadixTree(rt.edges[i].radixTree).
 					SetParent(income)
@@ -426,7 +570,7 @@ func (rt *RadixTree) insert(
 				nedge.radixTree.setParent(nedge)
 
 				rt.edges[i].radixTree = income.
-					setEdges([]*edge{nedge}).
+					setEdges([]*edge[V]{nedge}).
 					addSuggestions(
 						upperKey+cPrefix,
 						rt.edges[i].radixTree.suggestions,
@@ -443,26 +587,26 @@ func (rt *RadixTree) insert(
 				rt1 := rt.edges[i].radixTree
 				rt2 := income
 
-				rt.edges[i].radixTree = NewRadixTree().
+				rt.edges[i].radixTree = New[V]().
 					setSuggestions(rt1.suggestions).
 					addSuggestion(upperKey+cPrefix, rt2, addSuggestionFunction).
 					setParent(rt.edges[i])
 
-				edge1 := newEdge().
+				edge1 := newEdge[V]().
 					SetLabel(strings.TrimPrefix(rt.edges[i].label, cPrefix)).
 					SetRadixTree(rt1).
 					SetParent(rt.edges[i].radixTree)
 
 				rt1.setParent(edge1)
 
-				edge2 := newEdge().
+				edge2 := newEdge[V]().
 					SetLabel(strings.TrimPrefix(key, cPrefix)).
 					SetRadixTree(rt2).
 					SetParent(rt.edges[i].radixTree)
 
 				rt2.setParent(edge2)
 
-				rt.edges[i].radixTree.setEdges([]*edge{edge1, edge2})
+				rt.edges[i].radixTree.addEdge(edge1, u).addEdge(edge2, u)
 
 				rt.edges[i].label = cPrefix
 
@@ -473,106 +617,309 @@ func (rt *RadixTree) insert(
 		}
 
 		// the string has not been meet before
-		edge := newEdge().SetLabel(key).SetRadixTree(income).SetParent(rt)
+		edge := newEdge[V]().SetLabel(key).SetRadixTree(income).SetParent(rt)
 		income.setParent(edge)
 
-		rt.edges = append(rt.edges, edge)
+		rt.addEdge(edge, u)
 	}
 
-	income := NewRadixTree().setValue(value)
+	income := New[V]().setValue(value)
 
 	insert(rt, "", key, income.addSuggestion(key, income, addSuggestionFunction))
 }
 
 // Find returns a value associated with the given key.
-func (rt *RadixTree) Find(key string) interface{} {
-	if key == "" {
-		return rt.value
+// It returns the zero value of V if the tree does not hold the key,
+// use Get to distinguish a stored zero value from an absent key.
+func (rt *Tree[V]) Find(key string) V {
+	value, _ := rt.Get(key)
+
+	return value
+}
+
+// Get returns a value associated with the given key and reports
+// whether the tree holds the key.
+func (rt *Tree[V]) Get(key string) (value V, ok bool) {
+	node := rt.lookup(rt.normalize(key))
+	if node == nil || !node.hasValue {
+		return value, false
 	}
 
-	for i := range rt.edges {
-		cPrefix := commonPrefix(key, rt.edges[i].label)
+	return node.value, true
+}
+
+// Has reports whether the tree holds the given key.
+func (rt *Tree[V]) Has(key string) bool {
+	_, ok := rt.Get(key)
+
+	return ok
+}
+
+// LongestPrefix returns the longest key of the tree which is a prefix of
+// the given str, its value and reports whether such key exists.
+func (rt *Tree[V]) LongestPrefix(str string) (key string, value V, ok bool) {
+	rt.walkPrefixesOf(str, func(k string, v V) bool {
+		key, value, ok = k, v, true
+
+		return true
+	})
+
+	return key, value, ok
+}
+
+// AllPrefixesOf returns all keys of the tree which are prefixes of
+// the given str ordered from the shortest one.
+func (rt *Tree[V]) AllPrefixesOf(str string) []KeyValue[V] {
+	out := []KeyValue[V]{}
+
+	rt.walkPrefixesOf(str, func(k string, v V) bool {
+		out = append(out, KeyValue[V]{Key: k, Value: v})
 
-		// key and label are completly different
-		if cPrefix == "" {
-			continue
+		return true
+	})
+
+	return out
+}
+
+// walkPrefixesOf calls fn for each key of the tree which is a prefix of
+// the given str from the shortest one until fn returns false.
+func (rt *Tree[V]) walkPrefixesOf(str string, fn WalkFunc[V]) {
+	str = rt.normalize(str)
+	node, rest, u := rt, str, rt.units()
+
+	for {
+		if node.hasValue &&
+			!fn(rt.display(node, str[:len(str)-len(rest)]), node.value) {
+			return
+		}
+
+		i := node.childIndex(rest, u)
+		if i == -1 || !u.hasPrefix(rest, node.edges[i].label) {
+			return
 		}
 
-		if cPrefix == rt.edges[i].label {
-			return rt.edges[i].radixTree.Find(strings.TrimPrefix(key, cPrefix))
+		rest = rest[len(node.edges[i].label):]
+		node = node.edges[i].radixTree
+	}
+}
+
+// lookup returns the node which exactly matches the given key.
+func (rt *Tree[V]) lookup(key string) *Tree[V] {
+	node, u := rt, rt.units()
+
+	for key != "" {
+		i := node.childIndex(key, u)
+		if i == -1 || !strings.HasPrefix(key, node.edges[i].label) {
+			return nil
 		}
+
+		key = key[len(node.edges[i].label):]
+		node = node.edges[i].radixTree
 	}
 
-	return nil
+	return node
 }
 
-// Suggestion represents key-value pair.
-type Suggestion struct {
-	Key   string
-	Value interface{}
+// lookupPrefix returns the highest node which subtree holds all keys
+// started with the given prefix and the key of the node.
+func (rt *Tree[V]) lookupPrefix(prefix string) (*Tree[V], string) {
+	node, rest, u := rt, prefix, rt.units()
+
+	for rest != "" {
+		i := node.childIndex(rest, u)
+		if i == -1 {
+			return nil, ""
+		}
+
+		label := node.edges[i].label
+		cPrefix := u.commonPrefix(rest, label)
+
+		// cPrefix should meet ether label or prefix
+		if cPrefix != rest && cPrefix != label {
+			return nil, ""
+		}
+
+		node = node.edges[i].radixTree
+
+		// key: he label: hello
+		if len(cPrefix) != len(label) {
+			return node, prefix[:len(prefix)-len(rest)] + label
+		}
+
+		rest = rest[len(cPrefix):]
+	}
+
+	return node, prefix
 }
 
-// ClosestSuggestions returns suggestions set stored in the node
-// which prefix is more closest to the given str.
-func (rt *RadixTree) ClosestSuggestions(str string) []Suggestion {
-	var reconstructKey func(rt *RadixTree, suffix string) string
+// Delete removes the given key and its value from the tree.
+// It returns false if the tree does not hold the key.
+func (rt *Tree[V]) Delete(key string) bool {
+	node := rt.lookup(rt.normalize(key))
+	if node == nil || !node.hasValue {
+		return false
+	}
+
+	var empty V
+
+	node.purgeSuggestions(rt, map[*Tree[V]]struct{}{node: {}})
+	node.value = empty
+	node.hasValue = false
+	rt.deleteDisplayKey(node)
+
+	if len(node.edges) == 0 && node != rt {
+		node.detach().compress()
+
+		return true
+	}
+
+	node.compress()
+
+	return true
+}
+
+// DeletePrefix removes all keys started with the given prefix from the tree.
+// It returns the number of removed keys.
+func (rt *Tree[V]) DeletePrefix(prefix string) int {
+	node, _ := rt.lookupPrefix(rt.normalize(prefix))
+	if node == nil {
+		return 0
+	}
+
+	removed := map[*Tree[V]]struct{}{}
+
+	var deepDive func(rt *Tree[V])
 
-	reconstructKey = func(rt *RadixTree, suffix string) string {
-		if rt.parent == nil {
-			return suffix
+	deepDive = func(rt *Tree[V]) {
+		if rt.hasValue {
+			removed[rt] = struct{}{}
 		}
 
-		return reconstructKey(
-			rt.parent.parent,
-			rt.parent.label+suffix,
-		)
+		for i := range rt.edges {
+			deepDive(rt.edges[i].radixTree)
+		}
 	}
 
-	createSuggestions := func(rts []*RadixTree) []Suggestion {
-		out := make([]Suggestion, len(rts))
+	deepDive(node)
 
-		for i := range rts {
-			out[i] = Suggestion{
-				Key:   reconstructKey(rts[i], ""),
-				Value: rts[i].value,
+	if len(removed) == 0 {
+		return 0
+	}
+
+	node.purgeSuggestions(rt, removed)
+
+	for n := range removed {
+		rt.deleteDisplayKey(n)
+	}
+
+	// the whole tree is affected, so nothing to detach
+	if node == rt || node.parent == nil {
+		var empty V
+
+		node.value = empty
+		node.hasValue = false
+		node.edges = nil
+		node.suggestions = nil
+
+		return len(removed)
+	}
+
+	node.detach().compress()
+
+	return len(removed)
+}
+
+// purgeSuggestions deletes the given nodes from suggestions sets of
+// the current node and all upper nodes. The root holds the top-K policy
+// of the tree if any.
+func (rt *Tree[V]) purgeSuggestions(
+	root *Tree[V], removed map[*Tree[V]]struct{},
+) {
+	if t := root.topKPolicy(); t != nil {
+		t.purge(rt, removed)
+
+		return
+	}
+
+	for node := rt; node != nil; {
+		suggestions := node.suggestions[:0]
+
+		for i := range node.suggestions {
+			if _, ok := removed[node.suggestions[i]]; !ok {
+				suggestions = append(suggestions, node.suggestions[i])
 			}
 		}
 
-		return out
+		node.suggestions = suggestions
+
+		if node.parent == nil {
+			return
+		}
+
+		node = node.parent.parent
 	}
+}
 
-	var deepDive func(rt *RadixTree, key string) []*RadixTree
+// detach deletes the current node from edges of the upper node.
+// It returns the upper node.
+func (rt *Tree[V]) detach() *Tree[V] {
+	parent := rt.parent.parent
 
-	deepDive = func(rt *RadixTree, key string) []*RadixTree {
-		if key == "" {
-			return rt.suggestions
+	for i := range parent.edges {
+		if parent.edges[i] == rt.parent {
+			parent.edges = append(parent.edges[:i], parent.edges[i+1:]...)
+
+			break
 		}
+	}
 
-		// find prefix among the edges
-		for i := range rt.edges {
-			cPrefix := commonPrefix(key, rt.edges[i].label)
+	rt.parent = nil
 
-			// cPrefix should meet ether label or prefix
-			if cPrefix != key && cPrefix != rt.edges[i].label {
-				continue
-			}
+	return parent
+}
 
-			// key: he label: hello
-			// key: hello  label: hello
-			if cPrefix == key || key == rt.edges[i].label {
-				return rt.edges[i].radixTree.suggestions
-			}
+// compress merges the current node without value with its only child
+// into one edge. It is the opposite operation of splitting edges on insert.
+func (rt *Tree[V]) compress() {
+	if rt.hasValue || rt.parent == nil || len(rt.edges) != 1 {
+		return
+	}
 
-			return deepDive(
-				rt.edges[i].radixTree,
-				strings.TrimPrefix(key, cPrefix),
-			)
+	child := rt.edges[0]
+
+	rt.parent.label += child.label
+	rt.parent.radixTree = child.radixTree
+	child.radixTree.setParent(rt.parent)
+}
+
+// KeyValue represents key-value pair.
+type KeyValue[V any] struct {
+	Key   string
+	Value V
+}
+
+// ClosestSuggestions returns suggestions set stored in the node
+// which prefix is more closest to the given str.
+func (rt *Tree[V]) ClosestSuggestions(str string) []KeyValue[V] {
+	createSuggestions := func(rts []*Tree[V]) []KeyValue[V] {
+		out := make([]KeyValue[V], len(rts))
+
+		for i := range rts {
+			out[i] = KeyValue[V]{
+				Key:   rt.display(rts[i], rts[i].key()),
+				Value: rts[i].value,
+			}
 		}
 
-		return []*RadixTree{}
+		return out
+	}
+
+	node, _ := rt.lookupPrefix(rt.normalize(str))
+	if node == nil {
+		return []KeyValue[V]{}
 	}
 
-	return createSuggestions(deepDive(rt, str))
+	return createSuggestions(node.suggestions)
 }
 
 type traversalMode int
@@ -583,90 +930,151 @@ const (
 )
 
 // AutoCompleteBroadTraversal returns closest node's values to the given str.
-// Tree traversal algorithms is broadly.
-func (rt *RadixTree) AutoCompleteBroadTraversal(
+// Tree traversal algorithms is broadly. Non-positive max means no limit.
+func (rt *Tree[V]) AutoCompleteBroadTraversal(
 	str string, max int,
-) []Suggestion {
+) []KeyValue[V] {
 	return rt.autoCompleteTraversal(str, max, traversalModeBroad)
 }
 
 // AutoCompleteDepthTraversal returns closest node's values to the given str.
-// Tree traversal algorithms is depthly.
-func (rt *RadixTree) AutoCompleteDepthTraversal(
+// Tree traversal algorithms is depthly. Non-positive max means no limit.
+func (rt *Tree[V]) AutoCompleteDepthTraversal(
 	str string, max int,
-) []Suggestion {
+) []KeyValue[V] {
 	return rt.autoCompleteTraversal(str, max, traversalModeDepth)
 }
 
-// nolint: funlen
-// linter: style with internal helper function for recursion call makes
-// it not rational to split the next procedure to into parts.
-func (rt *RadixTree) autoCompleteTraversal(
+// autoCompleteTraversal walks the subtree of the node which prefix is
+// the closest to the given str. Found nodes are queued in the result
+// buffer itself and their keys are restored by parent pointers into one
+// string at the end. Nothing is allocated per visited node or returned
+// suggestion: for positive max up to the preallocated capacity the buffer,
+// the keys and the result are allocated once, bigger max only makes
+// the buffer grow.
+func (rt *Tree[V]) autoCompleteTraversal(
 	str string, max int, traversalMode traversalMode,
-) []Suggestion {
-	type rtree struct {
-		key string
-		*RadixTree
+) []KeyValue[V] {
+	node, key := rt.lookupPrefix(rt.normalize(str))
+	if node == nil {
+		return []KeyValue[V]{}
 	}
 
-	var childrenWithValue func(edges []*edge, prefix string) []rtree
+	found := make([]*Tree[V], 0, suggestionsCap(max))
+
+	full := func() bool {
+		return max > 0 && len(found) == max
+	}
+
+	switch traversalMode {
+	case traversalModeBroad:
+		// childrenWithValue adds the closest children holding values
+		var childrenWithValue func(rt *Tree[V])
+
+		childrenWithValue = func(rt *Tree[V]) {
+			for i := 0; i < len(rt.edges) && !full(); i++ {
+				child := rt.edges[i].radixTree
 
-	childrenWithValue = func(edges []*edge, prefix string) []rtree {
-		out := []rtree{}
+				if child.hasValue {
+					found = append(found, child)
 
-		for i := range edges {
-			if edges[i].radixTree.value != nil {
-				out = append(out, rtree{
-					prefix + edges[i].label,
-					edges[i].radixTree,
-				})
+					continue
+				}
 
-				continue
+				childrenWithValue(child)
 			}
+		}
 
-			out = append(
-				out, childrenWithValue(
-					edges[i].radixTree.edges, prefix+edges[i].label)...)
+		if node.hasValue {
+			found = append(found, node)
 		}
 
-		return out
-	}
+		childrenWithValue(node)
 
-	var deepDive func(
-		rt rtree, todo []rtree, out []Suggestion,
-	) []Suggestion
+		// found is the queue, nodes are expanded in order they are found
+		for i := 0; i < len(found) && !full(); i++ {
+			if found[i] != node {
+				childrenWithValue(found[i])
+			}
+		}
+	case traversalModeDepth:
+		var deepDive func(rt *Tree[V])
 
-	deepDive = func(
-		rt rtree, todo []rtree, out []Suggestion,
-	) []Suggestion {
-		if rt.value != nil {
-			out = append(out, Suggestion{
-				Key:   rt.key,
-				Value: rt.value,
-			})
+		deepDive = func(rt *Tree[V]) {
+			if rt.hasValue && !full() {
+				found = append(found, rt)
+			}
 
-			if len(out) == max {
-				return out
+			for i := 0; i < len(rt.edges) && !full(); i++ {
+				deepDive(rt.edges[i].radixTree)
 			}
 		}
 
-		switch traversalMode {
-		case traversalModeBroad:
-			todo = append(todo, childrenWithValue(rt.edges, rt.key)...)
-		case traversalModeDepth:
-			todo = append(childrenWithValue(rt.edges, rt.key), todo...)
+		deepDive(node)
+	}
+
+	// keyLen returns the length of the key of a node of the subtree.
+	keyLen := func(rt *Tree[V]) int {
+		n := len(key)
+
+		for ; rt != node; rt = rt.parent.parent {
+			n += len(rt.parent.label)
 		}
 
-		for i := range todo {
-			if commonPrefix(str, todo[i].key) != str {
-				continue
-			}
+		return n
+	}
+
+	var keys strings.Builder
+
+	size := 0
+	for i := range found {
+		size += keyLen(found[i])
+	}
+
+	keys.Grow(size)
+
+	var writeKey func(rt *Tree[V])
+
+	writeKey = func(rt *Tree[V]) {
+		if rt == node {
+			keys.WriteString(key)
 
-			return deepDive(todo[i], todo[i+1:], out)
+			return
 		}
 
-		return out
+		writeKey(rt.parent.parent)
+		keys.WriteString(rt.parent.label)
+	}
+
+	for i := range found {
+		writeKey(found[i])
+	}
+
+	joined := keys.String()
+	out := make([]KeyValue[V], len(found))
+
+	for i, start := 0, 0; i < len(found); i++ {
+		end := start + keyLen(found[i])
+
+		out[i] = KeyValue[V]{
+			Key:   rt.display(found[i], joined[start:end]),
+			Value: found[i].value,
+		}
+
+		start = end
+	}
+
+	return out
+}
+
+// suggestionsCap returns the capacity to preallocate for at most max
+// suggestions.
+func suggestionsCap(max int) int {
+	const maxPreallocated = 64
+
+	if max <= 0 || max > maxPreallocated {
+		return maxPreallocated
 	}
 
-	return deepDive(rtree{"", rt}, []rtree{}, []Suggestion{})
+	return max
 }
@@ -0,0 +1,880 @@
+package goradix
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func allocsTree() *Tree[int] {
+	rt := New[int]()
+
+	for i, key := range []string{
+		"romane", "romanus", "romulus", "rubens", "ruber", "rubicon",
+		"rubicundus", "toast", "toaster", "toasting", "слово", "словарь",
+	} {
+		rt.Insert(key, i)
+	}
+
+	for i := 0; i < 1000; i++ {
+		rt.Insert(fmt.Sprintf("rubi%d", i), i)
+	}
+
+	return rt
+}
+
+func TestCommonPrefix(t *testing.T) {
+	tests := []struct {
+		a, b, want string
+	}{
+		{"", "", ""},
+		{"hello", "head", "he"},
+		{"he", "hello", "he"},
+		{"hello", "hello", "hello"},
+		{"abc", "xyz", ""},
+		{"словарь", "слово", "слов"},
+		// é and è differ in the second byte of the rune
+		{"é", "è", ""},
+		{"café", "cafè", "caf"},
+		{"\xc3a", "\xc3b", "\xc3"},
+	}
+
+	for _, tt := range tests {
+		if got := commonPrefix(tt.a, tt.b); got != tt.want {
+			t.Errorf("commonPrefix(%q, %q) = %q; want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFindDoesNotAllocate(t *testing.T) {
+	rt := allocsTree()
+
+	for _, key := range []string{"romulus", "rubi500", "словарь", "rubi", "x"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			rt.Find(key)
+			rt.Get(key)
+			rt.Has(key)
+		})
+
+		if allocs != 0 {
+			t.Errorf("Find(%q) allocates %v times; want 0", key, allocs)
+		}
+	}
+}
+
+func TestAutoCompleteAllocs(t *testing.T) {
+	rt := allocsTree()
+
+	// the found nodes, their keys and the result are allocated once
+	// whatever number of nodes is visited
+	const maxAllocs = 3
+
+	for _, str := range []string{"", "r", "rubi", "romulus"} {
+		for _, max := range []int{1, 10, 64} {
+			broad := testing.AllocsPerRun(100, func() {
+				rt.AutoCompleteBroadTraversal(str, max)
+			})
+			depth := testing.AllocsPerRun(100, func() {
+				rt.AutoCompleteDepthTraversal(str, max)
+			})
+
+			if broad > maxAllocs || depth > maxAllocs {
+				t.Errorf("AutoComplete(%q, %d) allocates %v (broad) and %v (depth)"+
+					" times; want at most %v", str, max, broad, depth, maxAllocs)
+			}
+		}
+	}
+}
+
+// acceptAll is a suggestion function which accepts every candidate.
+func acceptAll[V any](
+	key string, currentSuggestions []*Tree[V], condidate *Tree[V],
+) []*Tree[V] {
+	return append(currentSuggestions, condidate)
+}
+
+// suggestedKeys returns sorted keys of ClosestSuggestions(str).
+func suggestedKeys[V any](rt *Tree[V], str string) []string {
+	keys := []string{}
+	for _, s := range rt.ClosestSuggestions(str) {
+		keys = append(keys, s.Key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// checkInvariants verifies parent pointers, order of edges, compression of
+// nodes and suggestions sets of the tree. If complete is true suggestions
+// set of each node must hold all nodes with values of its subtree.
+func checkInvariants[V any](t *testing.T, rt *Tree[V], complete bool) {
+	t.Helper()
+
+	u := rt.units()
+
+	var check func(node *Tree[V], key string) map[*Tree[V]]bool
+
+	check = func(node *Tree[V], key string) map[*Tree[V]]bool {
+		valued := map[*Tree[V]]bool{}
+		if node.hasValue {
+			valued[node] = true
+		}
+
+		for i, e := range node.edges {
+			if e.label == "" {
+				t.Errorf("node %q has an edge with empty label", key)
+			}
+
+			if e.parent != node {
+				t.Errorf("edge %q has wrong parent", key+e.label)
+			}
+
+			if e.radixTree.parent != e {
+				t.Errorf("node %q has wrong parent", key+e.label)
+			}
+
+			if i > 0 && u.first(node.edges[i-1].label) >= u.first(e.label) {
+				t.Errorf("edges of node %q are not sorted: %q, %q",
+					key, node.edges[i-1].label, e.label)
+			}
+
+			if e.radixTree.options != nil {
+				t.Errorf("node %q holds options of the tree", key+e.label)
+			}
+
+			if u.graphemes && len(graphemeCommonPrefix(key+e.label, key)) != len(key) {
+				t.Errorf("edge %q breaks a grapheme cluster of %q", e.label, key+e.label)
+			}
+
+			for n := range check(e.radixTree, key+e.label) {
+				valued[n] = true
+			}
+		}
+
+		if node != rt && !node.hasValue && len(node.edges) < 2 {
+			t.Errorf("node %q without value has %d edges",
+				key, len(node.edges))
+		}
+
+		seen := map[*Tree[V]]bool{}
+
+		for _, s := range node.suggestions {
+			if !valued[s] {
+				t.Errorf("node %q suggests a node out of its subtree", key)
+			}
+
+			if seen[s] {
+				t.Errorf("node %q suggests a node twice", key)
+			}
+
+			seen[s] = true
+		}
+
+		if complete && len(seen) != len(valued) {
+			t.Errorf("node %q suggests %d nodes; want %d",
+				key, len(seen), len(valued))
+		}
+
+		return valued
+	}
+
+	valued := check(rt, "")
+
+	// original keys are kept only for nodes of the tree with values
+	if rt.options != nil {
+		for node, display := range rt.options.displayKeys {
+			if !valued[node] {
+				t.Errorf("original key %q of a node out of the tree", display)
+			} else if key := node.key(); display == key {
+				t.Errorf("original key %q is the same as the key", display)
+			}
+		}
+	}
+}
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{
+			name: "empty tree",
+			want: ". \n",
+		},
+		{
+			name: "the string has not been meet before",
+			keys: []string{"toast", "rubens"},
+			want: ". \n├──'rubens' (value: 1)\n└──'toast' (value: 0)\n",
+		},
+		{
+			name: "key: hello label: he",
+			keys: []string{"he", "hello"},
+			want: ". \n└──'he' (value: 0)\n     └──'llo' (value: 1)\n",
+		},
+		{
+			name: "key: he label: hello",
+			keys: []string{"hello", "he"},
+			want: ". \n└──'he' (value: 1)\n     └──'llo' (value: 0)\n",
+		},
+		{
+			name: "key: hello label: head",
+			keys: []string{"head", "hello"},
+			want: ". \n└──'he'\n     ├──'ad' (value: 0)\n     └──'llo' (value: 1)\n",
+		},
+		{
+			name: "key meets the node without value",
+			keys: []string{"head", "hello", "he"},
+			want: ". \n└──'he' (value: 2)\n     ├──'ad' (value: 0)\n     └──'llo' (value: 1)\n",
+		},
+		{
+			name: "dublicate value! overwrite!",
+			keys: []string{"hello", "hello"},
+			want: ". \n└──'hello' (value: 1)\n",
+		},
+		{
+			name: "multibyte runes",
+			keys: []string{"café", "cafè"},
+			want: ". \n└──'caf'\n     ├──'è' (value: 1)\n     └──'é' (value: 0)\n",
+		},
+		{
+			name: "empty key",
+			keys: []string{"", "a"},
+			want: ". \n└──'a' (value: 1)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := New[int]()
+
+			model := map[string]int{}
+
+			for i, key := range tt.keys {
+				rt.InsertWithAddSuggestionFunction(key, i, acceptAll[int])
+				model[key] = i
+			}
+
+			if got := rt.StringValues(); got != tt.want {
+				t.Errorf("StringValues() = \n%s\nwant\n%s", got, tt.want)
+			}
+
+			for key, want := range model {
+				if v, ok := rt.Get(key); !ok || v != want {
+					t.Errorf("Get(%q) = %v, %v; want %v, true", key, v, ok, want)
+				}
+			}
+
+			checkInvariants(t, rt, true)
+		})
+	}
+}
+
+func TestInsertIntoNodeWithoutValue(t *testing.T) {
+	rt := New[int]()
+
+	// "he" meets the node made by splitting the edge, "rube" meets the node
+	// left by Delete
+	for i, key := range []string{"head", "hello", "he", "rube", "rubens"} {
+		rt.InsertWithAddSuggestionFunction(key, i, acceptAll[int])
+	}
+
+	rt.Delete("rube")
+	rt.InsertWithAddSuggestionFunction("rube", 5, acceptAll[int])
+
+	tests := []struct {
+		str  string
+		want []string
+	}{
+		{"", []string{"he", "head", "hello", "rube", "rubens"}},
+		{"h", []string{"he", "head", "hello"}},
+		{"he", []string{"he", "head", "hello"}},
+		{"rub", []string{"rube", "rubens"}},
+		{"rube", []string{"rube", "rubens"}},
+	}
+
+	for _, tt := range tests {
+		if got := suggestedKeys(rt, tt.str); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ClosestSuggestions(%q) = %v; want %v", tt.str, got, tt.want)
+		}
+	}
+
+	checkInvariants(t, rt, true)
+}
+
+func TestInsertOverwrite(t *testing.T) {
+	// big values only get into the sets
+	big := func(key string, set []*Tree[int], candidate *Tree[int]) []*Tree[int] {
+		if candidate.value < 10 {
+			return set
+		}
+
+		return append(set, candidate)
+	}
+
+	rt := New[int]()
+
+	for key, value := range map[string]int{
+		"rube": 100, "ruber": 200, "rubens": 3, "rubi": 4,
+	} {
+		rt.InsertWithAddSuggestionFunction(key, value, big)
+	}
+
+	rt.InsertWithAddSuggestionFunction("rubens", 300, big)
+	rt.InsertWithAddSuggestionFunction("ruber", 2, big)
+	rt.InsertWithAddSuggestionFunction("rube", 1000, big)
+
+	tests := []struct {
+		str  string
+		want []string
+	}{
+		{"", []string{"rube", "rubens"}},
+		{"rub", []string{"rube", "rubens"}},
+		{"rube", []string{"rube", "rubens"}},
+		{"ruber", []string{}},
+		{"rubens", []string{"rubens"}},
+		{"rubi", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := suggestedKeys(rt, tt.str); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ClosestSuggestions(%q) = %v; want %v", tt.str, got, tt.want)
+		}
+	}
+
+	if v, _ := rt.Get("rube"); v != 1000 {
+		t.Errorf("Get(%q) = %d; want %d", "rube", v, 1000)
+	}
+}
+
+func TestInsertOverwriteWithoutSuggestionFunc(t *testing.T) {
+	rt := New[int]()
+
+	for i, key := range []string{"rubens", "ruber", "rubicon"} {
+		rt.InsertWithAddSuggestionFunction(key, i, acceptAll[int])
+	}
+
+	// Insert has no SuggestionFunc, so the node stays in its place
+	rt.Insert("ruber", 42)
+
+	all := []KeyValue[int]{{"rubens", 0}, {"ruber", 42}, {"rubicon", 2}}
+
+	tests := []struct {
+		str  string
+		want []KeyValue[int]
+	}{
+		{"", all},
+		{"rub", all},
+		{"rube", all[:2]},
+		{"ruber", all[1:2]},
+	}
+
+	for _, tt := range tests {
+		if got := rt.ClosestSuggestions(tt.str); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ClosestSuggestions(%q) = %v; want %v", tt.str, got, tt.want)
+		}
+	}
+
+	checkInvariants(t, rt, true)
+}
+
+func TestDelete(t *testing.T) {
+	keys := []string{"rube", "ruber", "rubens", "rubi", "rubicundus", "rubicon"}
+
+	tests := []struct {
+		name   string
+		delete string
+		ok     bool
+		want   string
+	}{
+		{
+			name:   "absent key",
+			delete: "rubicund",
+			want: ". \n└──'rub'\n     ├──'e' (value: 0)\n     │    ├──'ns' (value: 2)\n" +
+				"     │    └──'r' (value: 1)\n     └──'i' (value: 3)\n" +
+				"          └──'c'\n               ├──'on' (value: 5)\n" +
+				"               └──'undus' (value: 4)\n",
+		},
+		{
+			name:   "leaf",
+			delete: "ruber",
+			ok:     true,
+			want: ". \n└──'rub'\n     ├──'e' (value: 0)\n     │    └──'ns' (value: 2)\n" +
+				"     └──'i' (value: 3)\n          └──'c'\n" +
+				"               ├──'on' (value: 5)\n               └──'undus' (value: 4)\n",
+		},
+		{
+			name:   "leaf leaves the node with the only child",
+			delete: "rubicon",
+			ok:     true,
+			want: ". \n└──'rub'\n     ├──'e' (value: 0)\n     │    ├──'ns' (value: 2)\n" +
+				"     │    └──'r' (value: 1)\n     └──'i' (value: 3)\n" +
+				"          └──'cundus' (value: 4)\n",
+		},
+		{
+			name:   "node with the only child",
+			delete: "rubi",
+			ok:     true,
+			want: ". \n└──'rub'\n     ├──'e' (value: 0)\n     │    ├──'ns' (value: 2)\n" +
+				"     │    └──'r' (value: 1)\n     └──'ic'\n" +
+				"          ├──'on' (value: 5)\n          └──'undus' (value: 4)\n",
+		},
+		{
+			name:   "node with children",
+			delete: "rube",
+			ok:     true,
+			want: ". \n└──'rub'\n     ├──'e'\n     │    ├──'ns' (value: 2)\n" +
+				"     │    └──'r' (value: 1)\n     └──'i' (value: 3)\n" +
+				"          └──'c'\n               ├──'on' (value: 5)\n" +
+				"               └──'undus' (value: 4)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := New[int]()
+
+			for i, key := range keys {
+				rt.InsertWithAddSuggestionFunction(key, i, acceptAll[int])
+			}
+
+			if ok := rt.Delete(tt.delete); ok != tt.ok {
+				t.Errorf("Delete(%q) = %v; want %v", tt.delete, ok, tt.ok)
+			}
+
+			if got := rt.StringValues(); got != tt.want {
+				t.Errorf("StringValues() = \n%s\nwant\n%s", got, tt.want)
+			}
+
+			if rt.Has(tt.delete) {
+				t.Errorf("Has(%q) = true after Delete", tt.delete)
+			}
+
+			for _, s := range rt.ClosestSuggestions("") {
+				if s.Key == tt.delete {
+					t.Errorf("ClosestSuggestions(%q) returns deleted key", "")
+				}
+			}
+
+			checkInvariants(t, rt, true)
+		})
+	}
+}
+
+func TestDeletePrefix(t *testing.T) {
+	keys := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon"}
+
+	tests := []struct {
+		prefix string
+		count  int
+		want   []string
+	}{
+		{prefix: "x", count: 0, want: keys},
+		{prefix: "rom", count: 3, want: []string{"rubens", "ruber", "rubicon"}},
+		{prefix: "roma", count: 2, want: []string{"romulus", "rubens", "ruber", "rubicon"}},
+		{prefix: "rube", count: 2, want: []string{"romane", "romanus", "romulus", "rubicon"}},
+		{prefix: "rubicon", count: 1, want: []string{"romane", "romanus", "romulus", "rubens", "ruber"}},
+		{prefix: "r", count: 6, want: []string{}},
+		{prefix: "", count: 6, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		rt := New[int]()
+
+		for i, key := range keys {
+			rt.InsertWithAddSuggestionFunction(key, i, acceptAll[int])
+		}
+
+		if count := rt.DeletePrefix(tt.prefix); count != tt.count {
+			t.Errorf("DeletePrefix(%q) = %d; want %d", tt.prefix, count, tt.count)
+		}
+
+		got := []string{}
+		for key := range rt.All() {
+			got = append(got, key)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DeletePrefix(%q) leaves %v; want %v", tt.prefix, got, tt.want)
+		}
+
+		if suggestions := rt.ClosestSuggestions(""); len(suggestions) != len(tt.want) {
+			t.Errorf("DeletePrefix(%q) leaves %d suggestions; want %d",
+				tt.prefix, len(suggestions), len(tt.want))
+		}
+
+		checkInvariants(t, rt, true)
+	}
+}
+
+func TestGet(t *testing.T) {
+	rt := New[int]()
+	rt.Insert("zero", 0)
+	rt.Insert("zeros", 2)
+	rt.Insert("one", 1)
+	rt.Insert("zebra", 3)
+
+	tests := []struct {
+		key   string
+		value int
+		ok    bool
+	}{
+		{"zero", 0, true},
+		{"zeros", 2, true},
+		{"one", 1, true},
+		// the root and the node made by splitting the edge hold no values
+		{"", 0, false},
+		{"ze", 0, false},
+		{"zer", 0, false},
+		{"zeroes", 0, false},
+		{"two", 0, false},
+	}
+
+	for _, tt := range tests {
+		if v, ok := rt.Get(tt.key); v != tt.value || ok != tt.ok {
+			t.Errorf("Get(%q) = %v, %v; want %v, %v", tt.key, v, ok, tt.value, tt.ok)
+		}
+
+		if ok := rt.Has(tt.key); ok != tt.ok {
+			t.Errorf("Has(%q) = %v; want %v", tt.key, ok, tt.ok)
+		}
+
+		if v := rt.Find(tt.key); v != tt.value {
+			t.Errorf("Find(%q) = %v; want %v", tt.key, v, tt.value)
+		}
+	}
+
+	if count := rt.NodeWithValueCount(); count != 4 {
+		t.Errorf("NodeWithValueCount() = %d; want 4", count)
+	}
+}
+
+func TestGetStoredNil(t *testing.T) {
+	rt := NewRadixTree()
+	rt.Insert("nil", nil)
+
+	if v, ok := rt.Get("nil"); !ok || v != nil {
+		t.Errorf("Get(%q) = %v, %v; want <nil>, true", "nil", v, ok)
+	}
+
+	if rt.Has("ni") {
+		t.Errorf("Has(%q) = true; want false", "ni")
+	}
+
+	if count := rt.NodeWithValueCount(); count != 1 {
+		t.Errorf("NodeWithValueCount() = %d; want 1", count)
+	}
+
+	if !rt.Delete("nil") || rt.Has("nil") {
+		t.Errorf("Delete(%q) leaves the stored nil", "nil")
+	}
+}
+
+func TestClosestSuggestions(t *testing.T) {
+	rt := New[int]()
+
+	asf := func(
+		key string, currentSuggestions []*Tree[int], condidate *Tree[int],
+	) []*Tree[int] {
+		if condidate.Value() >= 10 {
+			return append(currentSuggestions, condidate)
+		}
+
+		return currentSuggestions
+	}
+
+	rt.InsertWithAddSuggestionFunction("rube", 100, asf)
+	rt.InsertWithAddSuggestionFunction("ruber", 200, asf)
+	rt.InsertWithAddSuggestionFunction("rubens", 3, asf)
+	rt.InsertWithAddSuggestionFunction("rubi", 4, asf)
+	rt.InsertWithAddSuggestionFunction("rubicundus", 500, asf)
+	rt.InsertWithAddSuggestionFunction("rubicon", 60, asf)
+
+	tests := []struct {
+		str  string
+		want []KeyValue[int]
+	}{
+		{"rub", []KeyValue[int]{{"rube", 100}, {"ruber", 200}, {"rubicundus", 500}, {"rubicon", 60}}},
+		{"ru", []KeyValue[int]{{"rube", 100}, {"ruber", 200}, {"rubicundus", 500}, {"rubicon", 60}}},
+		{"rubi", []KeyValue[int]{{"rubicundus", 500}, {"rubicon", 60}}},
+		{"rubicu", []KeyValue[int]{{"rubicundus", 500}}},
+		{"rubens", []KeyValue[int]{}},
+		{"x", []KeyValue[int]{}},
+	}
+
+	for _, tt := range tests {
+		if got := rt.ClosestSuggestions(tt.str); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ClosestSuggestions(%q) = %v; want %v", tt.str, got, tt.want)
+		}
+	}
+
+	checkInvariants(t, rt, false)
+}
+
+func TestAutoComplete(t *testing.T) {
+	rt := NewRadixTree()
+	rt.Insert("rube", "one")
+	rt.Insert("ruber", 3)
+	rt.Insert("rubens", "2+2")
+	rt.Insert("rubi", "two")
+	rt.Insert("rubicundus", 5)
+	rt.Insert("rubicon", 6)
+	rt.Insert("r", 7)
+
+	tests := []struct {
+		str   string
+		max   int
+		broad []Suggestion
+		depth []Suggestion
+	}{
+		{
+			str: "rub",
+			max: 6,
+			broad: []Suggestion{{"rube", "one"}, {"rubi", "two"}, {"rubens", "2+2"},
+				{"ruber", 3}, {"rubicon", 6}, {"rubicundus", 5}},
+			depth: []Suggestion{{"rube", "one"}, {"rubens", "2+2"}, {"ruber", 3},
+				{"rubi", "two"}, {"rubicon", 6}, {"rubicundus", 5}},
+		},
+		{
+			str:   "rub",
+			max:   2,
+			broad: []Suggestion{{"rube", "one"}, {"rubi", "two"}},
+			depth: []Suggestion{{"rube", "one"}, {"rubens", "2+2"}},
+		},
+		{
+			str:   "rubic",
+			max:   0,
+			broad: []Suggestion{{"rubicon", 6}, {"rubicundus", 5}},
+			depth: []Suggestion{{"rubicon", 6}, {"rubicundus", 5}},
+		},
+		{
+			str:   "rubx",
+			max:   5,
+			broad: []Suggestion{},
+			depth: []Suggestion{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := rt.AutoCompleteBroadTraversal(tt.str, tt.max); !reflect.DeepEqual(got, tt.broad) {
+			t.Errorf("AutoCompleteBroadTraversal(%q, %d) = %v; want %v",
+				tt.str, tt.max, got, tt.broad)
+		}
+
+		if got := rt.AutoCompleteDepthTraversal(tt.str, tt.max); !reflect.DeepEqual(got, tt.depth) {
+			t.Errorf("AutoCompleteDepthTraversal(%q, %d) = %v; want %v",
+				tt.str, tt.max, got, tt.depth)
+		}
+	}
+}
+
+func TestAutoCompleteBelowUpperKey(t *testing.T) {
+	rt := New[int]()
+
+	for i, key := range []string{"", "rub", "rubi", "rubicon", "rubicundus", "rubens"} {
+		rt.Insert(key, i)
+	}
+
+	// both traversals start at the node of the prefix, so upper keys
+	// neither hide nor join suggestions
+	tests := []struct {
+		str  string
+		want []string
+	}{
+		{"rubi", []string{"rubi", "rubicon", "rubicundus"}},
+		{"rubic", []string{"rubicon", "rubicundus"}},
+		{"rube", []string{"rubens"}},
+		{"r", []string{"rub", "rubens", "rubi", "rubicon", "rubicundus"}},
+		{"", []string{"", "rub", "rubens", "rubi", "rubicon", "rubicundus"}},
+	}
+
+	for _, tt := range tests {
+		if got := keysOf(rt.AutoCompleteBroadTraversal(tt.str, 10)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AutoCompleteBroadTraversal(%q) = %v; want %v", tt.str, got, tt.want)
+		}
+
+		if got := keysOf(rt.AutoCompleteDepthTraversal(tt.str, 10)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AutoCompleteDepthTraversal(%q) = %v; want %v", tt.str, got, tt.want)
+		}
+	}
+}
+
+func TestLongestPrefix(t *testing.T) {
+	rt := New[int]()
+
+	for i, key := range []string{"/", "/api", "/api/v1/", "/api/v1/users"} {
+		rt.Insert(key, i)
+	}
+
+	// the empty key is a prefix of any str
+	withEmpty := New[int]()
+	withEmpty.Insert("", 10)
+	withEmpty.Insert("/api", 11)
+
+	tests := []struct {
+		tree     *Tree[int]
+		str      string
+		key      string
+		value    int
+		ok       bool
+		prefixes []string
+	}{
+		{rt, "/api/v1/users/42", "/api/v1/users", 3, true, []string{"/", "/api", "/api/v1/", "/api/v1/users"}},
+		{rt, "/api/v2", "/api", 1, true, []string{"/", "/api"}},
+		{rt, "/api/v1", "/api", 1, true, []string{"/", "/api"}},
+		{rt, "/", "/", 0, true, []string{"/"}},
+		{rt, "api", "", 0, false, []string{}},
+		{rt, "", "", 0, false, []string{}},
+		{withEmpty, "", "", 10, true, []string{""}},
+		{withEmpty, "x", "", 10, true, []string{""}},
+		{withEmpty, "/api/v1", "/api", 11, true, []string{"", "/api"}},
+	}
+
+	for _, tt := range tests {
+		key, value, ok := tt.tree.LongestPrefix(tt.str)
+		if key != tt.key || value != tt.value || ok != tt.ok {
+			t.Errorf("LongestPrefix(%q) = %q, %v, %v; want %q, %v, %v",
+				tt.str, key, value, ok, tt.key, tt.value, tt.ok)
+		}
+
+		if prefixes := keysOf(tt.tree.AllPrefixesOf(tt.str)); !reflect.DeepEqual(prefixes, tt.prefixes) {
+			t.Errorf("AllPrefixesOf(%q) = %v; want %v", tt.str, prefixes, tt.prefixes)
+		}
+	}
+}
+
+// fuzzAlphabet is a small alphabet which makes fuzzed keys share prefixes.
+var fuzzAlphabet = []string{"a", "b", "é", "è", "r"}
+
+// fuzzOps decodes data into operations on a tree.
+func fuzzOps(data []byte, fn func(op byte, key string, value int)) {
+	for i := 0; i+1 < len(data); {
+		op, size := data[i]%4, int(data[i+1]%6)
+		i += 2
+
+		var key strings.Builder
+		for ; size > 0 && i < len(data); size, i = size-1, i+1 {
+			key.WriteString(fuzzAlphabet[int(data[i])%len(fuzzAlphabet)])
+		}
+
+		fn(op, key.String(), i)
+	}
+}
+
+func FuzzTree(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0})
+	f.Add([]byte{0, 2, 2, 3, 0, 2, 2, 2, 1, 2, 2, 3, 3, 1, 2})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rt := New[int]()
+		model := map[string]int{}
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			switch op {
+			case 0, 1:
+				rt.InsertWithAddSuggestionFunction(key, value, acceptAll[int])
+				model[key] = value
+			case 2:
+				_, ok := model[key]
+				if deleted := rt.Delete(key); deleted != ok {
+					t.Fatalf("Delete(%q) = %v; want %v", key, deleted, ok)
+				}
+
+				delete(model, key)
+			case 3:
+				count := 0
+
+				for k := range model {
+					if strings.HasPrefix(k, key) {
+						delete(model, k)
+						count++
+					}
+				}
+
+				if deleted := rt.DeletePrefix(key); deleted != count {
+					t.Fatalf("DeletePrefix(%q) = %d; want %d", key, deleted, count)
+				}
+			}
+		})
+
+		checkInvariants(t, rt, true)
+
+		keys := make([]string, 0, len(model))
+		for key := range model {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		got := []string{}
+		for key, value := range rt.All() {
+			if value != model[key] {
+				t.Fatalf("All() yields %q: %d; want %d", key, value, model[key])
+			}
+
+			got = append(got, key)
+		}
+
+		if !reflect.DeepEqual(got, keys) && len(keys) != 0 {
+			t.Fatalf("All() = %v; want %v", got, keys)
+		}
+
+		for _, key := range keys {
+			if v, ok := rt.Get(key); !ok || v != model[key] {
+				t.Fatalf("Get(%q) = %v, %v; want %v, true", key, v, ok, model[key])
+			}
+		}
+
+		if count := rt.NodeWithValueCount(); count != len(model) {
+			t.Fatalf("NodeWithValueCount() = %d; want %d", count, len(model))
+		}
+
+		fuzzOps(data, func(_ byte, prefix string, _ int) {
+			want := []string{}
+
+			for _, key := range keys {
+				if strings.HasPrefix(key, prefix) {
+					want = append(want, key)
+				}
+			}
+
+			depth := []string{}
+			for _, s := range rt.AutoCompleteDepthTraversal(prefix, 0) {
+				depth = append(depth, s.Key)
+			}
+
+			if !reflect.DeepEqual(depth, want) {
+				t.Fatalf("AutoCompleteDepthTraversal(%q) = %v; want %v",
+					prefix, depth, want)
+			}
+
+			broad := []string{}
+			for _, s := range rt.AutoCompleteBroadTraversal(prefix, 0) {
+				broad = append(broad, s.Key)
+			}
+
+			sort.Strings(broad)
+
+			if !reflect.DeepEqual(broad, want) {
+				t.Fatalf("AutoCompleteBroadTraversal(%q) = %v; want %v",
+					prefix, broad, want)
+			}
+
+			closest := []string{}
+			for _, s := range rt.ClosestSuggestions(prefix) {
+				closest = append(closest, s.Key)
+			}
+
+			sort.Strings(closest)
+
+			if !reflect.DeepEqual(closest, want) {
+				t.Fatalf("ClosestSuggestions(%q) = %v; want %v",
+					prefix, closest, want)
+			}
+		})
+	})
+}
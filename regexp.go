@@ -0,0 +1,126 @@
+package goradix
+
+import "regexp/syntax"
+
+// regexpState is a state of the regexp automaton: instructions of threads
+// waiting for the next rune and the last matched rune.
+type regexpState struct {
+	pcs  []uint32
+	prev rune
+}
+
+// regexpAutomaton is the NFA of a compiled regular expression.
+type regexpAutomaton struct {
+	prog *syntax.Prog
+}
+
+// closure returns instructions matching runes or the end of the key
+// which are reachable from the given ones with the empty-width context
+// between the runes prev and next.
+func (a regexpAutomaton) closure(pcs []uint32, prev, next rune) []uint32 {
+	ctx := syntax.EmptyOpContext(prev, next)
+	visited := make([]bool, len(a.prog.Inst))
+	out := []uint32{}
+
+	var follow func(pc uint32)
+
+	follow = func(pc uint32) {
+		if visited[pc] {
+			return
+		}
+
+		visited[pc] = true
+		inst := &a.prog.Inst[pc]
+
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			follow(inst.Out)
+			follow(inst.Arg)
+		case syntax.InstCapture, syntax.InstNop:
+			follow(inst.Out)
+		case syntax.InstEmptyWidth:
+			if syntax.EmptyOp(inst.Arg)&^ctx == 0 {
+				follow(inst.Out)
+			}
+		case syntax.InstMatch, syntax.InstRune, syntax.InstRune1,
+			syntax.InstRuneAny, syntax.InstRuneAnyNotNL:
+			out = append(out, pc)
+		}
+	}
+
+	for _, pc := range pcs {
+		follow(pc)
+	}
+
+	return out
+}
+
+// step returns the state after matching the rune and reports whether
+// the key still can match.
+func (a regexpAutomaton) step(state regexpState, r rune) (regexpState, bool) {
+	visited := make([]bool, len(a.prog.Inst))
+	next := regexpState{prev: r}
+
+	for _, pc := range a.closure(state.pcs, state.prev, r) {
+		inst := &a.prog.Inst[pc]
+
+		var matched bool
+
+		switch inst.Op {
+		case syntax.InstRune, syntax.InstRune1:
+			matched = inst.MatchRune(r)
+		case syntax.InstRuneAny:
+			matched = true
+		case syntax.InstRuneAnyNotNL:
+			matched = r != '\n'
+		}
+
+		if matched && !visited[inst.Out] {
+			visited[inst.Out] = true
+			next.pcs = append(next.pcs, inst.Out)
+		}
+	}
+
+	return next, len(next.pcs) != 0
+}
+
+// accepts reports whether the whole key is matched.
+func (a regexpAutomaton) accepts(state regexpState) bool {
+	for _, pc := range a.closure(state.pcs, state.prev, -1) {
+		if a.prog.Inst[pc].Op == syntax.InstMatch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acceptsAll is always false: the automaton is not analyzed for
+// expressions matching any continuation.
+func (a regexpAutomaton) acceptsAll(regexpState) bool {
+	return false
+}
+
+// RegexpSearch returns key-value pairs which whole keys match the regular
+// expression in lexicographic order of keys. The expression has the syntax
+// of the regexp package and is matched against keys as "^(?:expr)$".
+// With WithKeyNormalizer it is matched against normalized keys.
+// The expression is compiled into an automaton stepped along edges, so
+// subtrees which keys are already rejected are skipped.
+// Non-positive max means no limit.
+func (rt *Tree[V]) RegexpSearch(expr string, max int) ([]KeyValue[V], error) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return nil, err
+	}
+
+	a := regexpAutomaton{prog: prog}
+	start := regexpState{pcs: []uint32{uint32(prog.Start)}, prev: -1}
+
+	return matchAutomaton[V, regexpState](rt, a, start, max), nil
+}
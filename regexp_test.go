@@ -0,0 +1,131 @@
+package goradix
+
+import (
+	"reflect"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// regexpReference returns keys of the tree which whole match expr.
+func regexpReference(rt *Tree[int], expr string) []string {
+	re := regexp.MustCompile("^(?:" + expr + ")$")
+	keys := []string{}
+
+	for key := range rt.All() {
+		if re.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func TestRegexpSearch(t *testing.T) {
+	rt := New[int]()
+	for i, key := range []string{
+		"", "rube", "ruber", "rubens", "rubi", "rubicundus", "rubicon",
+		"romane", "romanus", "romulus", "слово", "слон", "line\nbreak", "a1", "a22",
+	} {
+		rt.Insert(key, i)
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"rub(e|i)c?.*", []string{"rube", "rubens", "ruber", "rubi", "rubicon", "rubicundus"}},
+		{"rom(an|ul)us", []string{"romanus", "romulus"}},
+		{"rub", []string{}},
+		{"rube[nr]s?", []string{"rubens", "ruber"}},
+		{"сло.", []string{"слон"}},
+		{`\p{Cyrillic}+`, []string{"слово", "слон"}},
+		{`a\d{2}`, []string{"a22"}},
+		{`(?i)RUBE`, []string{"rube"}},
+		{`^rubi$`, []string{"rubi"}},
+		{`line.break`, []string{}},
+		{`(?s)line.break`, []string{"line\nbreak"}},
+		{`\brubi\b`, []string{"rubi"}},
+		{`.*us`, []string{"romanus", "romulus", "rubicundus"}},
+		{"", []string{""}},
+		{".*", nil},
+		{`(?m)line$\n^break`, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := rt.RegexpSearch(tt.expr, 0)
+		if err != nil {
+			t.Fatalf("RegexpSearch(%q) error = %v", tt.expr, err)
+		}
+
+		want := tt.want
+		if want == nil {
+			want = regexpReference(rt, tt.expr)
+		}
+
+		if keys := keysOf(got); !reflect.DeepEqual(keys, want) {
+			t.Errorf("RegexpSearch(%q) = %q; want %q", tt.expr, keys, want)
+		}
+
+		if ref := regexpReference(rt, tt.expr); !reflect.DeepEqual(keysOf(got), ref) {
+			t.Errorf("RegexpSearch(%q) = %q; regexp gives %q", tt.expr, keysOf(got), ref)
+		}
+	}
+
+	if got, _ := rt.RegexpSearch("rub.*", 2); !reflect.DeepEqual(keysOf(got), []string{"rube", "rubens"}) {
+		t.Errorf("RegexpSearch(%q, 2) = %q", "rub.*", keysOf(got))
+	}
+
+	if _, err := rt.RegexpSearch("rub(", 0); err == nil {
+		t.Errorf("RegexpSearch(%q) error = nil", "rub(")
+	} else if _, ok := err.(*syntax.Error); !ok {
+		t.Errorf("RegexpSearch(%q) error = %T; want *syntax.Error", "rub(", err)
+	}
+}
+
+// regexpAlphabet makes fuzzed expressions of the fuzz alphabet and
+// regular expression syntax.
+var regexpAlphabet = []string{
+	"a", "b", "é", "r", ".", "*", "+", "?", "(a|b)", "[^a]", `\b`, "^", "$", "(?i)A",
+}
+
+func FuzzRegexpSearch(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2}, []byte{4, 5, 0})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0}, []byte{8, 6, 10, 4, 5})
+
+	f.Fuzz(func(t *testing.T, data []byte, exprData []byte) {
+		rt := New[int]()
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			if op == 3 {
+				rt.Delete(key)
+			} else {
+				rt.Insert(key, value)
+			}
+		})
+
+		var expr strings.Builder
+		for _, b := range exprData {
+			expr.WriteString(regexpAlphabet[int(b)%len(regexpAlphabet)])
+		}
+
+		if _, err := regexp.Compile(expr.String()); err != nil {
+			if _, err := rt.RegexpSearch(expr.String(), 0); err == nil {
+				t.Fatalf("RegexpSearch(%q) error = nil", expr.String())
+			}
+
+			return
+		}
+
+		got, err := rt.RegexpSearch(expr.String(), 0)
+		if err != nil {
+			t.Fatalf("RegexpSearch(%q) error = %v", expr.String(), err)
+		}
+
+		if keys, want := keysOf(got), regexpReference(rt, expr.String()); !slices.Equal(keys, want) {
+			t.Fatalf("RegexpSearch(%q) = %q; want %q", expr.String(), keys, want)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package goradix
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// SuffixIndex finds keys by any substring, not only by prefixes.
+// Every suffix of an inserted key is stored in a radix tree pointing
+// back to the key, so a substring of the key is a prefix of one of its
+// suffixes. The index takes memory quadratic in lengths of keys, so it
+// suits short keys like words and names.
+type SuffixIndex[V any] struct {
+	keys     *Tree[V]
+	suffixes *Tree[[]string]
+}
+
+// NewSuffixIndex creates a new empty suffix index. Options configure
+// the tree of keys like in New, WithKeyNormalizer makes substring
+// queries normalized as well.
+func NewSuffixIndex[V any](opts ...Option[V]) *SuffixIndex[V] {
+	return &SuffixIndex[V]{
+		keys:     New[V](opts...),
+		suffixes: New[[]string](),
+	}
+}
+
+// Insert adds a key-value pair to the index.
+// The value of already indexed key is overwritten.
+func (si *SuffixIndex[V]) Insert(key string, value V) {
+	normalized := si.keys.normalize(key)
+	indexed := si.keys.Has(key)
+
+	si.keys.Insert(key, value)
+
+	if indexed {
+		return
+	}
+
+	si.eachSuffix(normalized, func(origins []string) []string {
+		i, _ := slices.BinarySearch(origins, normalized)
+
+		return slices.Insert(origins, i, normalized)
+	})
+}
+
+// Delete removes the given key and its value from the index.
+// It returns false if the index does not hold the key.
+func (si *SuffixIndex[V]) Delete(key string) bool {
+	normalized := si.keys.normalize(key)
+
+	if !si.keys.Delete(key) {
+		return false
+	}
+
+	si.eachSuffix(normalized, func(origins []string) []string {
+		if i, ok := slices.BinarySearch(origins, normalized); ok {
+			origins = slices.Delete(origins, i, i+1)
+		}
+
+		return origins
+	})
+
+	return true
+}
+
+// eachSuffix replaces origin keys of each suffix of the key by ones
+// returned by fn. Suffixes left without origin keys are removed.
+func (si *SuffixIndex[V]) eachSuffix(
+	key string, fn func(origins []string) []string,
+) {
+	for i := 0; i < len(key); {
+		suffix := key[i:]
+		origins, _ := si.suffixes.Get(suffix)
+
+		if origins = fn(origins); len(origins) == 0 {
+			si.suffixes.Delete(suffix)
+		} else {
+			si.suffixes.Insert(suffix, origins)
+		}
+
+		_, size := utf8.DecodeRuneInString(suffix)
+		i += size
+	}
+}
+
+// Get returns a value associated with the given key and reports
+// whether the index holds the key.
+func (si *SuffixIndex[V]) Get(key string) (V, bool) {
+	return si.keys.Get(key)
+}
+
+// Contains returns key-value pairs which keys contain the given substr.
+// Each key is returned once, keys are ordered by their suffixes started
+// with substr. The empty key has no suffixes, so it is never returned.
+// Non-positive max means no limit.
+func (si *SuffixIndex[V]) Contains(substr string, max int) []KeyValue[V] {
+	out := make([]KeyValue[V], 0, suggestionsCap(max))
+	seen := map[string]struct{}{}
+
+	si.suffixes.WalkPrefix(si.keys.normalize(substr),
+		func(suffix string, origins []string) bool {
+			for _, key := range origins {
+				if _, ok := seen[key]; ok {
+					continue
+				}
+
+				seen[key] = struct{}{}
+
+				node := si.keys.lookup(key)
+				out = append(out, KeyValue[V]{
+					Key: si.keys.display(node, key), Value: node.value,
+				})
+
+				if max > 0 && len(out) >= max {
+					return false
+				}
+			}
+
+			return true
+		})
+
+	return out
+}
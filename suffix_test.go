@@ -0,0 +1,157 @@
+package goradix
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// containsReference returns keys of the model which contain substr.
+func containsReference(model map[string]int, substr string) []string {
+	keys := []string{}
+
+	for key := range model {
+		if key != "" && strings.Contains(key, substr) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// checkContains compares results of Contains with the reference ones.
+func checkContains(t *testing.T, si *SuffixIndex[int], model map[string]int, substr string) {
+	t.Helper()
+
+	got := si.Contains(substr, 0)
+	keys := keysOf(got)
+	slices.Sort(keys)
+
+	if want := containsReference(model, substr); !slices.Equal(keys, want) {
+		t.Fatalf("Contains(%q) = %q; want %q", substr, keys, want)
+	}
+
+	for _, kv := range got {
+		if kv.Value != model[kv.Key] {
+			t.Fatalf("Contains(%q) value of %q = %v; want %v",
+				substr, kv.Key, kv.Value, model[kv.Key])
+		}
+	}
+
+	for max := 1; max <= len(got); max++ {
+		if page := si.Contains(substr, max); !slices.Equal(page, got[:max]) {
+			t.Fatalf("Contains(%q, %d) = %v; want %v", substr, max, page, got[:max])
+		}
+	}
+}
+
+func TestSuffixIndex(t *testing.T) {
+	si := NewSuffixIndex[int]()
+	model := map[string]int{
+		"rubicon": 1, "rubicundus": 2, "romane": 3, "conrad": 4,
+		"icon": 5, "слон": 6, "con": 7,
+	}
+
+	for key, value := range model {
+		si.Insert(key, value)
+	}
+
+	for _, substr := range []string{"con", "ic", "on", "r", "ло", "n", "x", ""} {
+		checkContains(t, si, model, substr)
+	}
+
+	// matches of substr in several suffixes of a key give it once
+	si.Insert("conconcon", 8)
+	model["conconcon"] = 8
+
+	checkContains(t, si, model, "con")
+
+	if v, ok := si.Get("conconcon"); !ok || v != 8 {
+		t.Errorf("Get(%q) = %v, %v; want 8, true", "conconcon", v, ok)
+	}
+
+	for _, key := range []string{"con", "rubicon", "conconcon"} {
+		if !si.Delete(key) {
+			t.Errorf("Delete(%q) = false", key)
+		}
+
+		delete(model, key)
+	}
+
+	if si.Delete("rubicon") {
+		t.Errorf("Delete(%q) of deleted key = true", "rubicon")
+	}
+
+	checkContains(t, si, model, "con")
+	checkContains(t, si, model, "")
+}
+
+func TestSuffixIndexNormalizer(t *testing.T) {
+	si := NewSuffixIndex(WithKeyNormalizer[int](searchNormalizer))
+	si.Insert("Rubén", 1)
+	si.Insert("Cañón", 2)
+
+	tests := map[string][]KeyValue[int]{
+		"BEN": {{"Rubén", 1}},
+		"ÑÓ":  {{"Cañón", 2}},
+		"é":   {{"Rubén", 1}},
+		"N":   {{"Cañón", 2}, {"Rubén", 1}},
+	}
+
+	for substr, want := range tests {
+		got := si.Contains(substr, 0)
+		slices.SortFunc(got, func(a, b KeyValue[int]) int { return strings.Compare(a.Key, b.Key) })
+
+		if !slices.Equal(got, want) {
+			t.Errorf("Contains(%q) = %v; want %v", substr, got, want)
+		}
+	}
+}
+
+func FuzzSuffixIndex(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		si := NewSuffixIndex[int]()
+		model := map[string]int{}
+		queries := []string{""}
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			switch op {
+			case 0, 1:
+				si.Insert(key, value)
+				model[key] = value
+			case 2:
+				_, ok := model[key]
+				if deleted := si.Delete(key); deleted != ok {
+					t.Fatalf("Delete(%q) = %v; want %v", key, deleted, ok)
+				}
+
+				delete(model, key)
+			case 3:
+				queries = append(queries, key)
+			}
+		})
+
+		for _, substr := range queries {
+			checkContains(t, si, model, substr)
+		}
+
+		// all suffixes of deleted keys are gone
+		for suffix, origins := range si.suffixes.All() {
+			for _, key := range origins {
+				if _, ok := model[key]; !ok || !strings.HasSuffix(key, suffix) {
+					t.Fatalf("suffix %q points to %q", suffix, key)
+				}
+			}
+		}
+
+		if got := maps.Collect(si.keys.All()); !maps.Equal(got, model) {
+			t.Fatalf("index holds %v; want %v", got, model)
+		}
+	})
+}
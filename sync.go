@@ -0,0 +1,406 @@
+package goradix
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// SyncTree is a radix tree safe for concurrent use by multiple goroutines.
+// Many readers are served concurrently, writers get exclusive access.
+// All returned suggestions are copies, so they stay valid after the tree
+// is changed. Iterators of Tree like All, Prefix, Seek and Range outlive
+// the call which returns them, so there are no such methods, use them
+// within View.
+type SyncTree[V any] struct {
+	mu   sync.RWMutex
+	tree *Tree[V]
+}
+
+// NewSyncTree creates a new empty concurrency-safe radix tree.
+// Options configure the tree like in New.
+func NewSyncTree[V any](opts ...Option[V]) *SyncTree[V] {
+	return &SyncTree[V]{tree: New[V](opts...)}
+}
+
+// View calls fn with the underlying tree under read lock.
+// It lets several queries see the same snapshot of the tree.
+// fn must not change the tree.
+func (st *SyncTree[V]) View(fn func(rt *Tree[V])) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	fn(st.tree)
+}
+
+// Update calls fn with the underlying tree under write lock.
+// It lets to apply several changes atomically.
+func (st *SyncTree[V]) Update(fn func(rt *Tree[V])) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	fn(st.tree)
+}
+
+// Insert adds a key-value pair to the tree.
+func (st *SyncTree[V]) Insert(key string, value V) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.tree.Insert(key, value)
+}
+
+// InsertWithAddSuggestionFunction add a key-pair to the tree.
+// See Tree.InsertWithAddSuggestionFunction for details.
+func (st *SyncTree[V]) InsertWithAddSuggestionFunction(
+	key string, value V, p SuggestionFunc[V],
+) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.tree.InsertWithAddSuggestionFunction(key, value, p)
+}
+
+// UpdateScore changes the value associated with the given key.
+// See Tree.UpdateScore for details.
+func (st *SyncTree[V]) UpdateScore(key string, value V) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.UpdateScore(key, value)
+}
+
+// Delete removes the given key and its value from the tree.
+// It returns false if the tree does not hold the key.
+func (st *SyncTree[V]) Delete(key string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.Delete(key)
+}
+
+// DeletePrefix removes all keys started with the given prefix from the tree.
+// It returns the number of removed keys.
+func (st *SyncTree[V]) DeletePrefix(prefix string) int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.DeletePrefix(prefix)
+}
+
+// BulkLoad adds key-value pairs read from r to the tree.
+// See Tree.BulkLoad for details.
+func (st *SyncTree[V]) BulkLoad(
+	r io.Reader, decodeValue func(data json.RawMessage) (V, error),
+) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.BulkLoad(r, decodeValue)
+}
+
+// BulkLoadWithAddSuggestionFunction adds key-value pairs read from r to
+// the tree. See Tree.BulkLoadWithAddSuggestionFunction for details.
+func (st *SyncTree[V]) BulkLoadWithAddSuggestionFunction(
+	r io.Reader,
+	decodeValue func(data json.RawMessage) (V, error),
+	p SuggestionFunc[V],
+) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.BulkLoadWithAddSuggestionFunction(r, decodeValue, p)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It replaces the content of the tree with the decoded one.
+func (st *SyncTree[V]) UnmarshalBinary(data []byte) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.UnmarshalBinary(data)
+}
+
+// ReadFrom reads the tree in the binary format from r and replaces
+// the content of the tree with it. See Tree.ReadFrom for details.
+func (st *SyncTree[V]) ReadFrom(r io.Reader) (int64, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.tree.ReadFrom(r)
+}
+
+// Find returns a value associated with the given key.
+func (st *SyncTree[V]) Find(key string) V {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Find(key)
+}
+
+// Get returns a value associated with the given key and reports
+// whether the tree holds the key.
+func (st *SyncTree[V]) Get(key string) (V, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Get(key)
+}
+
+// Has reports whether the tree holds the given key.
+func (st *SyncTree[V]) Has(key string) bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Has(key)
+}
+
+// LongestPrefix returns the longest key of the tree which is a prefix of
+// the given str, its value and reports whether such key exists.
+func (st *SyncTree[V]) LongestPrefix(str string) (string, V, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.LongestPrefix(str)
+}
+
+// Floor returns the greatest key of the tree which is less than or equal
+// to the given key, its value and reports whether such key exists.
+func (st *SyncTree[V]) Floor(key string) (string, V, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Floor(key)
+}
+
+// Ceiling returns the least key of the tree which is greater than or equal
+// to the given key, its value and reports whether such key exists.
+func (st *SyncTree[V]) Ceiling(key string) (string, V, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Ceiling(key)
+}
+
+// Min returns the least key of the tree, its value and reports whether
+// the tree is not empty.
+func (st *SyncTree[V]) Min() (string, V, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Min()
+}
+
+// Max returns the greatest key of the tree, its value and reports whether
+// the tree is not empty.
+func (st *SyncTree[V]) Max() (string, V, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Max()
+}
+
+// Walk calls fn for each key-value pair of the tree in lexicographic order
+// of keys until fn returns false. fn is called under read lock, so it must
+// not change the tree.
+func (st *SyncTree[V]) Walk(fn WalkFunc[V]) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	st.tree.Walk(fn)
+}
+
+// WalkPrefix calls fn for each key-value pair which key starts with
+// the given prefix in lexicographic order of keys until fn returns false.
+// fn is called under read lock, so it must not change the tree.
+func (st *SyncTree[V]) WalkPrefix(prefix string, fn WalkFunc[V]) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	st.tree.WalkPrefix(prefix, fn)
+}
+
+// AllPrefixesOf returns all keys of the tree which are prefixes of
+// the given str ordered from the shortest one.
+func (st *SyncTree[V]) AllPrefixesOf(str string) []KeyValue[V] {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.AllPrefixesOf(str)
+}
+
+// ClosestSuggestions returns suggestions set stored in the node
+// which prefix is more closest to the given str.
+func (st *SyncTree[V]) ClosestSuggestions(str string) []KeyValue[V] {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.ClosestSuggestions(str)
+}
+
+// AutoCompleteBroadTraversal returns closest node's values to the given str.
+// Tree traversal algorithms is broadly.
+func (st *SyncTree[V]) AutoCompleteBroadTraversal(
+	str string, max int,
+) []KeyValue[V] {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.AutoCompleteBroadTraversal(str, max)
+}
+
+// AutoCompleteDepthTraversal returns closest node's values to the given str.
+// Tree traversal algorithms is depthly.
+func (st *SyncTree[V]) AutoCompleteDepthTraversal(
+	str string, max int,
+) []KeyValue[V] {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.AutoCompleteDepthTraversal(str, max)
+}
+
+// AutoCompleteBroadTraversalPage returns the page of results of
+// AutoCompleteBroadTraversal continuing the given cursor and the cursor of
+// the next page. See Tree.AutoCompleteBroadTraversalPage for details.
+func (st *SyncTree[V]) AutoCompleteBroadTraversalPage(
+	str string, max int, c Cursor,
+) ([]KeyValue[V], Cursor, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.AutoCompleteBroadTraversalPage(str, max, c)
+}
+
+// AutoCompleteDepthTraversalPage returns the page of results of
+// AutoCompleteDepthTraversal continuing the given cursor and the cursor of
+// the next page. See Tree.AutoCompleteDepthTraversalPage for details.
+func (st *SyncTree[V]) AutoCompleteDepthTraversalPage(
+	str string, max int, c Cursor,
+) ([]KeyValue[V], Cursor, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.AutoCompleteDepthTraversalPage(str, max, c)
+}
+
+// ClosestSuggestionsPage returns the page of results of ClosestSuggestions
+// continuing the given cursor and the cursor of the next page.
+// See Tree.ClosestSuggestionsPage for details.
+func (st *SyncTree[V]) ClosestSuggestionsPage(
+	str string, max int, c Cursor,
+) ([]KeyValue[V], Cursor, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.ClosestSuggestionsPage(str, max, c)
+}
+
+// FuzzyAutoComplete returns values of keys which start with str written
+// with at most maxEdits typos. See Tree.FuzzyAutoComplete for details.
+func (st *SyncTree[V]) FuzzyAutoComplete(
+	str string, maxEdits int, max int,
+) []KeyValue[V] {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.FuzzyAutoComplete(str, maxEdits, max)
+}
+
+// Match returns key-value pairs which keys match the glob pattern.
+// See Tree.Match for details.
+func (st *SyncTree[V]) Match(pattern string, max int) ([]KeyValue[V], error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Match(pattern, max)
+}
+
+// RegexpSearch returns key-value pairs which whole keys match the regular
+// expression. See Tree.RegexpSearch for details.
+func (st *SyncTree[V]) RegexpSearch(expr string, max int) ([]KeyValue[V], error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.RegexpSearch(expr, max)
+}
+
+// Export writes all key-value pairs of the tree to w in the given
+// JSON format. See Tree.Export for details.
+func (st *SyncTree[V]) Export(w io.Writer, format ExportFormat) error {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.Export(w, format)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// See Tree.MarshalBinary for details.
+func (st *SyncTree[V]) MarshalBinary() ([]byte, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.MarshalBinary()
+}
+
+// WriteTo writes the tree in the binary format to w.
+// It implements io.WriterTo.
+func (st *SyncTree[V]) WriteTo(w io.Writer) (int64, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.WriteTo(w)
+}
+
+// WriteIndex writes the tree in the flat index format served by
+// MappedTree. See Tree.WriteIndex for details.
+func (st *SyncTree[V]) WriteIndex(w io.Writer) (int64, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.WriteIndex(w)
+}
+
+// NodeWithValueCount returns total count of nodes which holding values.
+func (st *SyncTree[V]) NodeWithValueCount() int {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.NodeWithValueCount()
+}
+
+// NodeWithValueCountByCounter returns total count of nodes which holding values.
+// The incoming counter desided how many should be add to a result count.
+func (st *SyncTree[V]) NodeWithValueCountByCounter(counter func(V) int) int {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.NodeWithValueCountByCounter(counter)
+}
+
+// StringValues returs a string representation of the radix tree.
+// Is aim to show the radix tree and holded values.
+func (st *SyncTree[V]) StringValues() string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.StringValues()
+}
+
+// StringSuggestions returs a string representation of the radix tree.
+// It aims to shot suggestions sets accosiated with each node of the tree.
+func (st *SyncTree[V]) StringSuggestions() string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.StringSuggestions()
+}
+
+// StringParentChild returs a string representation of the radix tree.
+// It aims to show parent-child relationships inside the tree.
+func (st *SyncTree[V]) StringParentChild() string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.tree.StringParentChild()
+}
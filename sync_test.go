@@ -0,0 +1,107 @@
+package goradix
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSyncTreeConcurrentReadWrite(t *testing.T) {
+	st := NewSyncTree[int]()
+
+	asf := func(
+		key string, currentSuggestions []*Tree[int], condidate *Tree[int],
+	) []*Tree[int] {
+		return append(currentSuggestions, condidate)
+	}
+
+	const writes = 500
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < writes; i++ {
+			st.InsertWithAddSuggestionFunction(fmt.Sprintf("rub%d", i), i, asf)
+
+			if i%10 == 0 {
+				st.Delete(fmt.Sprintf("rub%d", i/2))
+			}
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < writes; i++ {
+				st.AutoCompleteBroadTraversal("rub", 10)
+				st.AutoCompleteDepthTraversal("rub1", 10)
+				st.ClosestSuggestions("rub")
+				st.Find(fmt.Sprintf("rub%d", i))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for i := writes / 2; i < writes; i++ {
+		key := fmt.Sprintf("rub%d", i)
+
+		if v, ok := st.Get(key); !ok || v != i {
+			t.Fatalf("Get(%q) = %v, %v; want %v, true", key, v, ok, i)
+		}
+	}
+}
+
+func TestSyncTreeView(t *testing.T) {
+	st := NewSyncTree[string]()
+
+	st.Update(func(rt *Tree[string]) {
+		rt.Insert("romane", "one")
+		rt.Insert("romanus", "two")
+	})
+
+	st.View(func(rt *Tree[string]) {
+		if got := rt.NodeWithValueCount(); got != 2 {
+			t.Fatalf("NodeWithValueCount() = %d; want 2", got)
+		}
+	})
+}
+
+func TestSyncTreeWalkAndSerialization(t *testing.T) {
+	st := NewSyncTree[int](WithValueCodec[int](intCodec{}))
+	st.Insert("romane", 1)
+	st.Insert("romanus", 2)
+
+	keys := []string{}
+	st.WalkPrefix("roman", func(key string, _ int) bool {
+		keys = append(keys, key)
+
+		return true
+	})
+
+	if want := []string{"romane", "romanus"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("WalkPrefix() keys = %v; want %v", keys, want)
+	}
+
+	data, err := st.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	restored := NewSyncTree[int](WithValueCodec[int](intCodec{}))
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+
+	if v, ok := restored.Get("romanus"); !ok || v != 2 {
+		t.Fatalf("Get(%q) = %v, %v; want 2, true", "romanus", v, ok)
+	}
+}
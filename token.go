@@ -0,0 +1,204 @@
+package goradix
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits a phrase into tokens indexed by TokenIndex.
+type Tokenizer func(phrase string) []string
+
+// SplitWords is the default Tokenizer which splits a phrase into runs of
+// letters and digits, so "the lord of the rings" gives five tokens.
+func SplitWords(phrase string) []string {
+	return strings.FieldsFunc(phrase, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// WithTokenizer sets the tokenizer of a TokenIndex. SplitWords is used
+// by default.
+func WithTokenizer[V any](tokenizer Tokenizer) Option[V] {
+	return func(o *options[V]) {
+		o.tokenizer = tokenizer
+	}
+}
+
+// TokenIndex finds phrases by prefixes of their tokens in any order:
+// "lord ri" finds "the lord of the rings". Each token of an inserted
+// phrase is stored in a radix tree with the posting set of phrases
+// holding it.
+type TokenIndex[V any] struct {
+	phrases *Tree[V]
+	tokens  *Tree[[]string]
+}
+
+// NewTokenIndex creates a new empty token index. Options configure
+// the tree of phrases like in New, WithKeyNormalizer normalizes tokens
+// of phrases and queries as well.
+func NewTokenIndex[V any](opts ...Option[V]) *TokenIndex[V] {
+	return &TokenIndex[V]{
+		phrases: New[V](opts...),
+		tokens:  New[[]string](),
+	}
+}
+
+// tokenize returns distinct tokens of the normalized phrase.
+func (ti *TokenIndex[V]) tokenize(phrase string) []string {
+	tokenizer := Tokenizer(SplitWords)
+	if ti.phrases.options != nil && ti.phrases.options.tokenizer != nil {
+		tokenizer = ti.phrases.options.tokenizer
+	}
+
+	tokens := tokenizer(phrase)
+	slices.Sort(tokens)
+
+	return slices.Compact(tokens)
+}
+
+// Insert adds a phrase and its value to the index.
+// The value of already indexed phrase is overwritten.
+func (ti *TokenIndex[V]) Insert(phrase string, value V) {
+	normalized := ti.phrases.normalize(phrase)
+	indexed := ti.phrases.Has(phrase)
+
+	ti.phrases.Insert(phrase, value)
+
+	if indexed {
+		return
+	}
+
+	ti.eachToken(normalized, func(postings []string) []string {
+		i, _ := slices.BinarySearch(postings, normalized)
+
+		return slices.Insert(postings, i, normalized)
+	})
+}
+
+// Delete removes the given phrase and its value from the index.
+// It returns false if the index does not hold the phrase.
+func (ti *TokenIndex[V]) Delete(phrase string) bool {
+	normalized := ti.phrases.normalize(phrase)
+
+	if !ti.phrases.Delete(phrase) {
+		return false
+	}
+
+	ti.eachToken(normalized, func(postings []string) []string {
+		if i, ok := slices.BinarySearch(postings, normalized); ok {
+			postings = slices.Delete(postings, i, i+1)
+		}
+
+		return postings
+	})
+
+	return true
+}
+
+// eachToken replaces the posting set of each token of the phrase by
+// the one returned by fn. Tokens left without phrases are removed.
+func (ti *TokenIndex[V]) eachToken(
+	phrase string, fn func(postings []string) []string,
+) {
+	for _, token := range ti.tokenize(phrase) {
+		postings, _ := ti.tokens.Get(token)
+
+		if postings = fn(postings); len(postings) == 0 {
+			ti.tokens.Delete(token)
+		} else {
+			ti.tokens.Insert(token, postings)
+		}
+	}
+}
+
+// Get returns a value associated with the given phrase and reports
+// whether the index holds the phrase.
+func (ti *TokenIndex[V]) Get(phrase string) (V, bool) {
+	return ti.phrases.Get(phrase)
+}
+
+// Search returns phrases which hold a token started with each token of
+// the query. Phrases are ranked by the number of query tokens matching
+// whole tokens of the phrase, then by the number of distinct tokens of
+// the phrase, so shorter phrases go first, and then by phrases.
+// Non-positive max means no limit.
+func (ti *TokenIndex[V]) Search(query string, max int) []KeyValue[V] {
+	type match struct {
+		phrase string
+		exact  int
+		tokens int
+	}
+
+	queryTokens := ti.tokenize(ti.phrases.normalize(query))
+	if len(queryTokens) == 0 {
+		return []KeyValue[V]{}
+	}
+
+	// postings of tokens started with each query token
+	sets := make([]map[string]struct{}, len(queryTokens))
+
+	for i, token := range queryTokens {
+		sets[i] = map[string]struct{}{}
+
+		for _, postings := range ti.tokens.Prefix(token) {
+			for _, phrase := range postings {
+				sets[i][phrase] = struct{}{}
+			}
+		}
+	}
+
+	// intersection starts with the smallest set
+	slices.SortFunc(sets, func(a, b map[string]struct{}) int {
+		return cmp.Compare(len(a), len(b))
+	})
+
+	matches := []match{}
+
+	for phrase := range sets[0] {
+		found := true
+
+		for _, set := range sets[1:] {
+			if _, found = set[phrase]; !found {
+				break
+			}
+		}
+
+		if !found {
+			continue
+		}
+
+		tokens := ti.tokenize(phrase)
+		exact := 0
+
+		for _, token := range queryTokens {
+			if _, ok := slices.BinarySearch(tokens, token); ok {
+				exact++
+			}
+		}
+
+		matches = append(matches, match{phrase, exact, len(tokens)})
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(b.exact, a.exact),
+			cmp.Compare(a.tokens, b.tokens),
+			strings.Compare(a.phrase, b.phrase),
+		)
+	})
+
+	if max > 0 && len(matches) > max {
+		matches = matches[:max]
+	}
+
+	out := make([]KeyValue[V], len(matches))
+
+	for i, m := range matches {
+		node := ti.phrases.lookup(m.phrase)
+		out[i] = KeyValue[V]{Key: ti.phrases.display(node, m.phrase), Value: node.value}
+	}
+
+	return out
+}
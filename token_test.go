@@ -0,0 +1,203 @@
+package goradix
+
+import (
+	"cmp"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// searchReference returns expected result of TokenIndex.Search.
+func searchReference(model map[string]int, query string) []string {
+	type match struct {
+		phrase        string
+		exact, tokens int
+	}
+
+	queryTokens := slices.Compact(slices.Sorted(slices.Values(SplitWords(query))))
+	if len(queryTokens) == 0 {
+		return []string{}
+	}
+
+	matches := []match{}
+
+	for phrase := range model {
+		tokens := SplitWords(phrase)
+		m := match{phrase: phrase, tokens: len(slices.Compact(slices.Sorted(slices.Values(tokens))))}
+		all := true
+
+		for _, q := range queryTokens {
+			all = all && slices.ContainsFunc(tokens, func(token string) bool {
+				return strings.HasPrefix(token, q)
+			})
+
+			if slices.Contains(tokens, q) {
+				m.exact++
+			}
+		}
+
+		if all {
+			matches = append(matches, m)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(cmp.Compare(b.exact, a.exact), cmp.Compare(a.tokens, b.tokens),
+			strings.Compare(a.phrase, b.phrase))
+	})
+
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.phrase
+	}
+
+	return out
+}
+
+func TestTokenIndex(t *testing.T) {
+	ti := NewTokenIndex[int]()
+	model := map[string]int{
+		"the lord of the rings":      1,
+		"the fellowship of the ring": 2,
+		"the two towers":             3,
+		"the return of the king":     4,
+		"rings of power":             5,
+		"lord":                       6,
+	}
+
+	for phrase, value := range model {
+		ti.Insert(phrase, value)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"lord ri", []string{"the lord of the rings"}},
+		{"ri lord", []string{"the lord of the rings"}},
+		{"lord", []string{"lord", "the lord of the rings"}},
+		{"ring", []string{"the fellowship of the ring", "rings of power", "the lord of the rings"}},
+		{"of the", []string{
+			"the fellowship of the ring", "the lord of the rings", "the return of the king",
+		}},
+		{"t", []string{
+			"the two towers", "the fellowship of the ring",
+			"the lord of the rings", "the return of the king",
+		}},
+		{"lord king", []string{}},
+		{"  ", []string{}},
+	}
+
+	for _, tt := range tests {
+		got := ti.Search(tt.query, 0)
+		if keys := keysOf(got); !reflect.DeepEqual(keys, tt.want) {
+			t.Errorf("Search(%q) = %q; want %q", tt.query, keys, tt.want)
+		}
+
+		if want := searchReference(model, tt.query); !reflect.DeepEqual(keysOf(got), want) {
+			t.Errorf("Search(%q) = %q; reference %q", tt.query, keysOf(got), want)
+		}
+
+		for _, kv := range got {
+			if kv.Value != model[kv.Key] {
+				t.Errorf("Search(%q) value of %q = %v; want %v",
+					tt.query, kv.Key, kv.Value, model[kv.Key])
+			}
+		}
+	}
+
+	if got := keysOf(ti.Search("the", 2)); !reflect.DeepEqual(got, []string{
+		"the two towers", "the fellowship of the ring",
+	}) {
+		t.Errorf("Search(%q, 2) = %q", "the", got)
+	}
+
+	if !ti.Delete("the lord of the rings") || ti.Delete("the lord of the rings") {
+		t.Errorf("Delete() of indexed and deleted phrases are wrong")
+	}
+
+	if got := ti.Search("lord ri", 0); len(got) != 0 {
+		t.Errorf("Search(%q) after Delete = %v", "lord ri", got)
+	}
+
+	if postings, ok := ti.tokens.Get("rings"); !ok || !slices.Equal(postings, []string{"rings of power"}) {
+		t.Errorf("postings of %q = %q, %v", "rings", postings, ok)
+	}
+}
+
+func TestTokenIndexOptions(t *testing.T) {
+	ti := NewTokenIndex(
+		WithKeyNormalizer[int](searchNormalizer),
+		WithTokenizer[int](func(phrase string) []string {
+			return strings.Split(phrase, "/")
+		}),
+	)
+
+	ti.Insert("Músicos/Rubén Blades", 1)
+	ti.Insert("Pintores/Rubens", 2)
+
+	want := []KeyValue[int]{{"Músicos/Rubén Blades", 1}, {"Pintores/Rubens", 2}}
+	if got := ti.Search("RUBEN", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Search(%q) = %v; want %v", "RUBEN", got, want)
+	}
+
+	want = []KeyValue[int]{{"Músicos/Rubén Blades", 1}}
+	if got := ti.Search("music/ruben b", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Search(%q) = %v; want %v", "music/ruben b", got, want)
+	}
+}
+
+// tokenAlphabet makes fuzzed phrases share tokens and their prefixes.
+var tokenAlphabet = []string{"a", "b", "ab", " ", "é"}
+
+func FuzzTokenIndex(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 4, 3, 1, 3, 1, 0, 3, 4, 3, 1, 2, 2, 4, 3})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ti := NewTokenIndex[int]()
+		model := map[string]int{}
+		queries := []string{}
+
+		for i := 0; i+1 < len(data); {
+			op, size := data[i]%4, int(data[i+1]%8)
+			i += 2
+
+			var phrase strings.Builder
+			for ; size > 0 && i < len(data); size, i = size-1, i+1 {
+				phrase.WriteString(tokenAlphabet[int(data[i])%len(tokenAlphabet)])
+			}
+
+			switch key := phrase.String(); op {
+			case 0, 1:
+				ti.Insert(key, i)
+				model[key] = i
+			case 2:
+				ti.Delete(key)
+				delete(model, key)
+			case 3:
+				queries = append(queries, key)
+			}
+		}
+
+		for _, query := range queries {
+			if got, want := keysOf(ti.Search(query, 0)), searchReference(model, query); !slices.Equal(got, want) {
+				t.Fatalf("Search(%q) = %q; want %q", query, got, want)
+			}
+		}
+
+		if got := maps.Collect(ti.phrases.All()); !maps.Equal(got, model) {
+			t.Fatalf("index holds %v; want %v", got, model)
+		}
+
+		for token, postings := range ti.tokens.All() {
+			for _, phrase := range postings {
+				if !slices.Contains(SplitWords(phrase), token) {
+					t.Fatalf("token %q points to %q", token, phrase)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package goradix
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// topK is a suggestion policy which keeps K nodes with the best scores
+// in suggestions set of each node. Sets are sorted by score descending,
+// nodes with the same score are sorted by keys.
+type topK[V any] struct {
+	k     int
+	score func(V) float64
+}
+
+// WithTopK makes the tree maintain suggestions sets itself: the set of
+// each node holds at most k nodes of its subtree with the biggest scores
+// in ranked order. Sets are kept up to date on insert and delete,
+// SuggestionFunc passed to InsertWithAddSuggestionFunction is ignored.
+// Non-positive k keeps all sets empty.
+func WithTopK[V any](k int, score func(V) float64) Option[V] {
+	return func(o *options[V]) {
+		o.topK = &topK[V]{k: max(k, 0), score: score}
+	}
+}
+
+// topKPolicy returns the top-K policy of the tree or nil.
+func (rt *Tree[V]) topKPolicy() *topK[V] {
+	if rt.options == nil {
+		return nil
+	}
+
+	return rt.options.topK
+}
+
+// less reports whether a ranks higher than b.
+func (t *topK[V]) less(a, b *Tree[V]) bool {
+	if sa, sb := t.score(a.value), t.score(b.value); sa != sb {
+		return sa > sb
+	}
+
+	return keyLess(a, b)
+}
+
+// offer adds the candidate to the ranked set if it is good enough.
+func (t *topK[V]) offer(set []*Tree[V], candidate *Tree[V]) []*Tree[V] {
+	i := sort.Search(len(set), func(i int) bool {
+		return t.less(candidate, set[i])
+	})
+
+	if i >= t.k {
+		return set
+	}
+
+	if len(set) < t.k {
+		set = append(set, nil)
+	}
+
+	copy(set[i+1:], set[i:])
+	set[i] = candidate
+
+	return set
+}
+
+// suggestionFunc returns the policy as a SuggestionFunc.
+func (t *topK[V]) suggestionFunc() SuggestionFunc[V] {
+	return func(
+		key string, currentSuggestions []*Tree[V], condidate *Tree[V],
+	) []*Tree[V] {
+		return t.offer(currentSuggestions, condidate)
+	}
+}
+
+// rebuild builds the set of the node from its value and sets of its
+// children. The removed nodes are skipped.
+func (t *topK[V]) rebuild(rt *Tree[V], removed map[*Tree[V]]struct{}) {
+	set := make([]*Tree[V], 0, min(t.k, len(rt.suggestions)+1))
+
+	if _, ok := removed[rt]; rt.hasValue && !ok {
+		set = t.offer(set, rt)
+	}
+
+	for _, e := range rt.edges {
+		for _, s := range e.radixTree.suggestions {
+			if _, ok := removed[s]; ok {
+				continue
+			}
+
+			// sets are ranked, so the rest of the set is worse
+			if len(set) == t.k && !t.less(s, set[len(set)-1]) {
+				break
+			}
+
+			set = t.offer(set, s)
+		}
+	}
+
+	rt.suggestions = set
+}
+
+// inserted updates sets of the inserted node and its upper nodes.
+// The tree must be inserted into without any SuggestionFunc.
+func (t *topK[V]) inserted(rt *Tree[V]) {
+	t.rebuild(rt, nil)
+
+	for node := rt.upper(); node != nil; node = node.upper() {
+		node.suggestions = t.offer(node.suggestions, rt)
+	}
+}
+
+// updated re-ranks the node which value is changed from old in sets of
+// the node and its upper nodes.
+func (t *topK[V]) updated(rt *Tree[V], old V) {
+	oldScore, newScore := t.score(old), t.score(rt.value)
+
+	for node := rt; node != nil; node = node.upper() {
+		i := slices.Index(node.suggestions, rt)
+
+		switch {
+		// the node is out of the set and stays there if it gets worse
+		case i == -1:
+			if newScore > oldScore {
+				node.suggestions = t.offer(node.suggestions, rt)
+			}
+		// a node out of the full set can outrank the worse one
+		case newScore < oldScore && len(node.suggestions) >= t.k:
+			t.rebuild(node, nil)
+		default:
+			node.suggestions = t.offer(
+				slices.Delete(node.suggestions, i, i+1), rt)
+		}
+	}
+}
+
+// purge deletes the removed nodes from sets of the node and its upper
+// nodes. Full sets which lost nodes are refilled from sets of children,
+// other sets already hold all suitable nodes.
+func (t *topK[V]) purge(rt *Tree[V], removed map[*Tree[V]]struct{}) {
+	for node := rt; node != nil; node = node.upper() {
+		full := len(node.suggestions) >= t.k
+
+		suggestions := node.suggestions[:0]
+
+		for _, s := range node.suggestions {
+			if _, ok := removed[s]; !ok {
+				suggestions = append(suggestions, s)
+			}
+		}
+
+		lost := len(suggestions) != len(node.suggestions)
+		node.suggestions = suggestions
+
+		if full && lost {
+			t.rebuild(node, removed)
+		}
+	}
+}
+
+// upper returns the upper node or nil for the root.
+func (rt *Tree[V]) upper() *Tree[V] {
+	if rt.parent == nil {
+		return nil
+	}
+
+	return rt.parent.parent
+}
+
+// key returns the key of the node.
+func (rt *Tree[V]) key() string {
+	labels := []string{}
+
+	for node := rt; node.parent != nil; node = node.upper() {
+		labels = append(labels, node.parent.label)
+	}
+
+	var key strings.Builder
+
+	for i := len(labels) - 1; i >= 0; i-- {
+		key.WriteString(labels[i])
+	}
+
+	return key.String()
+}
+
+// keyLess reports whether the key of a is less than the key of b without
+// restoring the keys. Edges are sorted, so keys of nodes are ordered as
+// nodes in preorder: an upper node goes first, otherwise the order is
+// given by the edges the paths to a and b diverge at.
+func keyLess[V any](a, b *Tree[V]) bool {
+	depth := func(rt *Tree[V]) int {
+		d := 0
+		for ; rt.parent != nil; rt = rt.upper() {
+			d++
+		}
+
+		return d
+	}
+
+	da, db := depth(a), depth(b)
+
+	for ; da > db; da-- {
+		a = a.upper()
+		if a == b {
+			return false
+		}
+	}
+
+	for ; db > da; db-- {
+		b = b.upper()
+		if b == a {
+			return true
+		}
+	}
+
+	if a == b {
+		return false
+	}
+
+	for a.upper() != b.upper() {
+		a, b = a.upper(), b.upper()
+	}
+
+	return a.parent.label < b.parent.label
+}
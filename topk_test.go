@@ -0,0 +1,226 @@
+package goradix
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// checkTopK verifies that suggestions set of each node holds k best scored
+// nodes of its subtree in ranked order.
+func checkTopK(t *testing.T, rt *Tree[int], k int, score func(int) float64) {
+	t.Helper()
+
+	all := map[string]int{}
+	for key, value := range rt.All() {
+		all[key] = value
+	}
+
+	for prefix, got := range suggestionSets(rt) {
+		want := []KeyValue[int]{}
+
+		for key, value := range all {
+			if strings.HasPrefix(key, prefix) {
+				want = append(want, KeyValue[int]{Key: key, Value: value})
+			}
+		}
+
+		sort.Slice(want, func(i, j int) bool {
+			if si, sj := score(want[i].Value), score(want[j].Value); si != sj {
+				return si > sj
+			}
+
+			return want[i].Key < want[j].Key
+		})
+
+		if len(want) > k {
+			want = want[:k]
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("suggestions of %q = %v; want %v", prefix, got, want)
+		}
+	}
+}
+
+// popularity scores values by tens, so there are many ties.
+func popularity(value int) float64 {
+	return float64(value / 10)
+}
+
+func TestTopK(t *testing.T) {
+	rt := New(WithTopK(2, popularity))
+
+	for key, value := range map[string]int{
+		"rube": 100, "ruber": 200, "rubens": 3, "rubi": 4,
+		"rubicundus": 500, "rubicon": 60, "слово": 70,
+	} {
+		rt.InsertWithAddSuggestionFunction(key, value, acceptAll[int])
+	}
+
+	want := []KeyValue[int]{{"rubicundus", 500}, {"ruber", 200}}
+	if got := rt.ClosestSuggestions("rub"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClosestSuggestions(%q) = %v; want %v", "rub", got, want)
+	}
+
+	rt.Insert("rubens", 1000)
+	rt.Delete("ruber")
+
+	want = []KeyValue[int]{{"rubens", 1000}, {"rube", 100}}
+	if got := rt.ClosestSuggestions("rube"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClosestSuggestions(%q) = %v; want %v", "rube", got, want)
+	}
+
+	want = []KeyValue[int]{{"rubens", 1000}, {"rubicundus", 500}}
+
+	if got := rt.ClosestSuggestions(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClosestSuggestions(%q) = %v; want %v", "", got, want)
+	}
+
+	checkTopK(t, rt, 2, popularity)
+}
+
+func TestTopKNonPositive(t *testing.T) {
+	for _, k := range []int{0, -1} {
+		rt := New(WithTopK(k, popularity))
+
+		for _, key := range []string{"rube", "ruber", "rubens", "rubi"} {
+			rt.Insert(key, len(key))
+		}
+
+		rt.Insert("rube", 100)
+		rt.Delete("ruber")
+		rt.DeletePrefix("rubi")
+
+		checkTopK(t, rt, 0, popularity)
+
+		if got := rt.ClosestSuggestions("rub"); len(got) != 0 {
+			t.Errorf("k = %d: ClosestSuggestions(%q) = %v; want none", k, "rub", got)
+		}
+	}
+}
+
+func TestUpdateScore(t *testing.T) {
+	rt := New(WithTopK(2, popularity))
+
+	for key, value := range map[string]int{
+		"rube": 100, "ruber": 200, "rubens": 30, "rubi": 40, "rubicon": 60,
+	} {
+		rt.Insert(key, value)
+	}
+
+	tests := []struct {
+		key   string
+		value int
+		ok    bool
+		want  []KeyValue[int]
+	}{
+		// gets into the set
+		{"rubens", 150, true, []KeyValue[int]{{"ruber", 200}, {"rubens", 150}}},
+		// moves within the set
+		{"rubens", 250, true, []KeyValue[int]{{"rubens", 250}, {"ruber", 200}}},
+		// is evicted by a node out of the set
+		{"ruber", 10, true, []KeyValue[int]{{"rubens", 250}, {"rube", 100}}},
+		{"rubex", 1000, false, []KeyValue[int]{{"rubens", 250}, {"rube", 100}}},
+	}
+
+	for _, tt := range tests {
+		if ok := rt.UpdateScore(tt.key, tt.value); ok != tt.ok {
+			t.Errorf("UpdateScore(%q, %d) = %v; want %v", tt.key, tt.value, ok, tt.ok)
+		}
+
+		if got := rt.ClosestSuggestions("r"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("after UpdateScore(%q, %d) ClosestSuggestions(%q) = %v; want %v",
+				tt.key, tt.value, "r", got, tt.want)
+		}
+
+		checkTopK(t, rt, 2, popularity)
+	}
+}
+
+func FuzzTopK(f *testing.F) {
+	f.Add([]byte{0, 3, 0, 1, 2, 0, 2, 0, 1, 1, 3, 0, 1, 2})
+	f.Add([]byte{0, 5, 4, 0, 1, 0, 1, 0, 3, 4, 0, 1, 2, 2, 4, 0})
+	f.Add([]byte{0, 2, 2, 3, 0, 2, 2, 2, 1, 2, 2, 3, 3, 1, 2})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rt := New(WithTopK(3, popularity))
+		pairs := map[string]int{}
+
+		fuzzOps(data, func(op byte, key string, value int) {
+			switch op {
+			case 0:
+				rt.Insert(key, value)
+				pairs[key] = value
+			case 1:
+				// values of updated keys move in both directions
+				value = pairs[key] + 25 - value%50
+
+				_, ok := pairs[key]
+				if updated := rt.UpdateScore(key, value); updated != ok {
+					t.Fatalf("UpdateScore(%q) = %v; want %v", key, updated, ok)
+				}
+
+				if ok {
+					pairs[key] = value
+				}
+			case 2:
+				rt.Delete(key)
+				delete(pairs, key)
+			case 3:
+				rt.DeletePrefix(key)
+				maps.DeleteFunc(pairs, func(k string, _ int) bool {
+					return strings.HasPrefix(k, key)
+				})
+			}
+		})
+
+		checkInvariants(t, rt, false)
+		checkTopK(t, rt, 3, popularity)
+
+		built, err := BuildFromSorted(func(yield func(string, int) bool) {
+			for _, key := range slices.Sorted(maps.Keys(pairs)) {
+				if !yield(key, pairs[key]) {
+					return
+				}
+			}
+		}, nil, WithTopK(3, popularity))
+		if err != nil {
+			t.Fatalf("BuildFromSorted() error = %v", err)
+		}
+
+		checkTopK(t, built, 3, popularity)
+
+		if !reflect.DeepEqual(suggestionSets(built), suggestionSets(rt)) {
+			t.Fatalf("BuildFromSorted() = %v; want %v",
+				suggestionSets(built), suggestionSets(rt))
+		}
+	})
+}
+
+func benchmarkInsert(b *testing.B, opts ...Option[int]) {
+	keys := make([]string, 200000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i * 7919 % len(keys))
+	}
+
+	for i := 0; i < b.N; i++ {
+		rt := New(opts...)
+
+		for i, key := range keys {
+			rt.Insert(key, i%50)
+		}
+	}
+}
+
+func BenchmarkInsertTopK(b *testing.B) {
+	benchmarkInsert(b, WithTopK(10, popularity))
+}
+
+func BenchmarkInsertWithoutTopK(b *testing.B) {
+	benchmarkInsert(b)
+}
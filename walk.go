@@ -0,0 +1,108 @@
+package goradix
+
+import "iter"
+
+// WalkFunc is a signature of the functions called for each key-value pair
+// during walking the tree. Walking stops when the function returns false.
+type WalkFunc[V any] func(key string, value V) bool
+
+// Walk calls fn for each key-value pair of the tree in lexicographic order
+// of keys until fn returns false. In grapheme clusters mode keys are
+// compared by grapheme clusters.
+func (rt *Tree[V]) Walk(fn WalkFunc[V]) {
+	rt.walk(rt, "", fn)
+}
+
+// WalkPrefix calls fn for each key-value pair which key starts with
+// the given prefix in lexicographic order of keys until fn returns false.
+func (rt *Tree[V]) WalkPrefix(prefix string, fn WalkFunc[V]) {
+	node, key := rt.lookupPrefix(rt.normalize(prefix))
+	if node == nil {
+		return
+	}
+
+	node.walk(rt, key, fn)
+}
+
+// All returns an iterator over all key-value pairs of the tree
+// in lexicographic order of keys.
+func (rt *Tree[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		rt.Walk(yield)
+	}
+}
+
+// Prefix returns an iterator over key-value pairs which key starts with
+// the given prefix in lexicographic order of keys.
+func (rt *Tree[V]) Prefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		rt.WalkPrefix(prefix, yield)
+	}
+}
+
+// walk is a helper function for preorder traversal of the tree.
+// Edges are sorted, so preorder traversal gives lexicographic order.
+// fn gets original keys of nodes kept by the root.
+// It returns false if walking has been stopped by fn.
+func (rt *Tree[V]) walk(root *Tree[V], key string, fn WalkFunc[V]) bool {
+	if rt.hasValue && !fn(root.display(rt, key), rt.value) {
+		return false
+	}
+
+	for i := range rt.edges {
+		if !rt.edges[i].radixTree.walk(root, key+rt.edges[i].label, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Walk calls fn for each key-value pair of the tree in lexicographic order
+// of keys until fn returns false.
+func (t *ImmutableTree[V]) Walk(fn WalkFunc[V]) {
+	t.root.walk(fn)
+}
+
+// WalkPrefix calls fn for each key-value pair which key starts with
+// the given prefix in lexicographic order of keys until fn returns false.
+func (t *ImmutableTree[V]) WalkPrefix(prefix string, fn WalkFunc[V]) {
+	n := t.root.lookupPrefix(prefix)
+	if n == nil {
+		return
+	}
+
+	n.walk(fn)
+}
+
+// All returns an iterator over all key-value pairs of the tree
+// in lexicographic order of keys.
+func (t *ImmutableTree[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.Walk(yield)
+	}
+}
+
+// Prefix returns an iterator over key-value pairs which key starts with
+// the given prefix in lexicographic order of keys.
+func (t *ImmutableTree[V]) Prefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		t.WalkPrefix(prefix, yield)
+	}
+}
+
+// walk is a helper function for preorder traversal of the tree.
+// It returns false if walking has been stopped by fn.
+func (n *inode[V]) walk(fn WalkFunc[V]) bool {
+	if n.leaf != nil && !fn(n.leaf.Key, n.leaf.Value) {
+		return false
+	}
+
+	for i := range n.edges {
+		if !n.edges[i].node.walk(fn) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,83 @@
+package goradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	rt := New[int]()
+
+	for i, key := range []string{
+		"toast", "rubicon", "romane", "rube", "ruber", "rubens", "a", "rubi", "",
+	} {
+		rt.Insert(key, i)
+	}
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"", -1, []string{"", "a", "romane", "rube", "rubens", "ruber", "rubi", "rubicon", "toast"}},
+		{"", 3, []string{"", "a", "romane"}},
+		{"rub", -1, []string{"rube", "rubens", "ruber", "rubi", "rubicon"}},
+		{"rubic", -1, []string{"rubicon"}},
+		{"rube", 1, []string{"rube"}},
+		{"x", -1, []string{}},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+
+		for key := range rt.Prefix(tt.prefix) {
+			if len(got) == tt.limit {
+				break
+			}
+
+			got = append(got, key)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Prefix(%q) = %v; want %v", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestWalkStops(t *testing.T) {
+	rt := New[int]()
+	it := NewImmutableTree[int]()
+
+	for i, key := range []string{"rube", "rubens", "ruber", "rubi", "rubicon", "toast"} {
+		rt.Insert(key, i)
+		it = it.Insert(key, i)
+	}
+
+	walks := []struct {
+		name string
+		walk func(fn WalkFunc[int])
+		want []string
+	}{
+		{"Walk", rt.Walk, []string{"rube", "rubens", "ruber", "rubi", "rubicon", "toast"}},
+		{"WalkPrefix", func(fn WalkFunc[int]) { rt.WalkPrefix("rubi", fn) }, []string{"rubi", "rubicon"}},
+		{"ImmutableTree.Walk", it.Walk, []string{"rube", "rubens", "ruber", "rubi", "rubicon", "toast"}},
+		{"ImmutableTree.WalkPrefix", func(fn WalkFunc[int]) { it.WalkPrefix("rube", fn) }, []string{"rube", "rubens", "ruber"}},
+	}
+
+	for _, w := range walks {
+		for limit := 0; limit <= len(w.want); limit++ {
+			got := []string{}
+
+			w.walk(func(key string, value int) bool {
+				got = append(got, key)
+
+				return len(got) < limit
+			})
+
+			want := w.want[:max(limit, 1)]
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s stopped after %d keys = %v; want %v", w.name, limit, got, want)
+			}
+		}
+	}
+}